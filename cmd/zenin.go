@@ -4,14 +4,24 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
 	g "github.com/jmkng/zenin/pkg/graphics"
 
 	"github.com/jmkng/zenin/internal/account"
+	"github.com/jmkng/zenin/internal/credential"
 	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/maintenance"
 	"github.com/jmkng/zenin/internal/measurement"
 	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/retention"
+	"github.com/jmkng/zenin/internal/rollup"
 	"github.com/jmkng/zenin/internal/settings"
+	"github.com/jmkng/zenin/internal/sink"
+	"github.com/jmkng/zenin/internal/stats"
 	"github.com/jmkng/zenin/repository"
 	"github.com/jmkng/zenin/server"
 )
@@ -64,10 +74,30 @@ func main() {
 	settings, err := ssv.GetSettings(ctx)
 	dd(err)
 
+	key, err := e.GetSecretKey()
+	dd(err)
+	cipher, err := credential.NewCipher(key)
+	dd(err)
+	crsv := credential.NewCredentialService(repository, cipher)
+
+	sinks, err := sink.NewExporters(e)
+	dd(err)
+	if len(sinks) > 0 {
+		names := []string{}
+		for _, v := range sinks {
+			names = append(names, v.Name())
+		}
+		env.Info("sinks", "count", len(sinks), "names", names)
+	}
+
 	mesv := measurement.NewMeasurementService(repository)
-	distributor := monitor.NewDistributor(mesv, settings)
+	distributor := monitor.NewDistributor(mesv, crsv, settings, int(e.ProbeWorkers), int(e.EventWorkers), sinks)
 	go distributor.Listen(channel)
 
+	masv := maintenance.NewMaintenanceService(repository, channel)
+	err = masv.Distribute(ctx)
+	dd(err)
+
 	active, err := mosv.GetActive(ctx)
 	dd(err)
 
@@ -79,15 +109,55 @@ func main() {
 	config, err := server.NewConfig(e)
 	dd(err)
 
+	signals, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	resv := retention.NewRetentionService(repository, repository, repository)
+	pruned := resv.Start(signals)
+	rosv := rollup.NewRollupService(repository, repository)
+	aggregated := rosv.Start(signals)
+	stsv := stats.NewStatsService(repository, repository)
+
 	err = server.NewServer(
 		config,
-		server.Services{Settings: ssv, Measurement: mesv, Monitor: mosv, Account: asv},
-	).Serve()
-	dd(err)
+		server.Services{Settings: ssv, Measurement: mesv, Monitor: mosv, Account: asv, Credential: crsv,
+			Maintenance: masv, Retention: resv, Rollup: rosv, Stats: stsv},
+	).Serve(signals, shutdownTimeout)
+	// Keep shutting down so measurements are not lost, but exit with an error status at the end.
+	failed := err != nil
+	if failed {
+		env.Error("server stopped with error", "error", err)
+	}
+	// A second signal will exit immediately, and the background services will stop.
+	stop()
+
+	drain, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+	if err := distributor.Shutdown(drain, channel); err != nil {
+		env.Warn("distributor did not finish before shutdown deadline, running probes were cancelled", "error", err)
+	}
+	flush, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+	if err := sinks.Close(flush); err != nil {
+		env.Warn("sinks did not finish before shutdown deadline, buffered measurements were dropped", "error", err)
+	}
+	// Running retention and rollup are cancelled with the signal context, but may
+	// still be using the repository.
+	<-pruned
+	<-aggregated
+	if err := repository.Close(); err != nil {
+		env.Error("failed to close repository", "error", err)
+	}
 
 	env.Debug("main stopping")
+	if failed {
+		os.Exit(1)
+	}
 }
 
+// shutdownTimeout is the time allowed for each stage of shutdown.
+const shutdownTimeout = 5 * time.Second
+
 // dd will log an error and exit, or do nothing if err == nil.
 func dd(err error) {
 	if err == nil {
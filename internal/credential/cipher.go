@@ -0,0 +1,60 @@
+package credential
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// NewCipher returns a new `Cipher` using the key, which must be 32 bytes.
+func NewCipher(key []byte) (Cipher, error) {
+	if len(key) != 32 {
+		return Cipher{}, errors.New("cipher key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return Cipher{}, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return Cipher{}, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return Cipher{aead: aead}, nil
+}
+
+// Cipher encrypts secrets before they are stored in the repository, using AES-256-GCM.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// Encrypt returns the encrypted plaintext as a base64 encoded string.
+// The random nonce is stored as a prefix of the ciphertext.
+func (c Cipher) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext from a string returned by `Encrypt`.
+func (c Cipher) Decrypt(value string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, errors.New("ciphertext is too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt ciphertext: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,116 @@
+package credential
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// Credential is the credential domain type.
+//
+// A credential contains a client certificate and private key presented to servers that require
+// mutual TLS, a bundle of CA certificates used to verify servers using a private PKI, or both.
+type Credential struct {
+	Id        *int               `json:"id" db:"credential_id"`
+	CreatedAt internal.TimeValue `json:"createdAt" db:"created_at"`
+	UpdatedAt internal.TimeValue `json:"updatedAt" db:"updated_at"`
+	Name      string             `json:"name" db:"name"`
+	// Certificate is a PEM encoded client certificate chain.
+	Certificate *string `json:"certificate" db:"certificate"`
+	// PrivateKey is the PEM encoded private key for `Certificate`, encrypted with `Cipher`.
+	//
+	// Never serialized.
+	PrivateKey *string `json:"-" db:"private_key"`
+	// CABundle is a list of PEM encoded CA certificates.
+	CABundle *string `json:"caBundle" db:"ca_bundle"`
+}
+
+// MarshalJSON implements `json.Marshaler` for `Credential`.
+//
+// Reports if the `Credential` has a private key, without including it.
+func (c Credential) MarshalJSON() ([]byte, error) {
+	type alias Credential
+	return json.Marshal(struct {
+		alias
+		HasPrivateKey bool `json:"hasPrivateKey"`
+	}{alias: alias(c), HasPrivateKey: c.PrivateKey != nil})
+}
+
+// Application represents an attempt to create or update a `Credential`.
+type Application struct {
+	Name        string  `json:"name"`
+	Certificate *string `json:"certificate"`
+	// PrivateKey is the PEM encoded private key for `Certificate`.
+	//
+	// When updating a `Credential`, the stored private key is kept if this is nil.
+	PrivateKey *string `json:"privateKey"`
+	CABundle   *string `json:"caBundle"`
+}
+
+// Validate will return an error if the `Application` is in an invalid state.
+//
+// The private key is only required if `requireKey` is true.
+// The error will always be `env.Validation`.
+func (a Application) Validate(requireKey bool) error {
+	validation := env.NewValidation()
+	if strings.TrimSpace(a.Name) == "" {
+		validation.Push("value for field `name` is required")
+	}
+	if a.Certificate == nil && a.CABundle == nil {
+		validation.Push("value for field `certificate` or `caBundle` is required")
+	}
+	if a.Certificate == nil && a.PrivateKey != nil {
+		validation.Push("value for field `certificate` is required with `privateKey`")
+	}
+	if a.Certificate != nil {
+		if a.PrivateKey == nil {
+			if requireKey {
+				validation.Push("value for field `privateKey` is required with `certificate`")
+			}
+		} else if _, err := tls.X509KeyPair([]byte(*a.Certificate), []byte(*a.PrivateKey)); err != nil {
+			validation.Push("values for fields `certificate` and `privateKey` must be a valid PEM encoded key pair")
+		}
+	}
+	if a.CABundle != nil {
+		if _, err := parseCABundle(*a.CABundle); err != nil {
+			validation.Push("value for field `caBundle` must contain PEM encoded certificates")
+		}
+	}
+
+	if !validation.Empty() {
+		return validation
+	}
+	return nil
+}
+
+// Material is the decrypted form of a `Credential`, ready to be applied to a TLS configuration.
+type Material struct {
+	// Certificate is the client certificate, or nil if the `Credential` has none.
+	Certificate *tls.Certificate
+	// RootCAs is the pool of CA certificates, or nil if the `Credential` has none.
+	RootCAs *x509.CertPool
+}
+
+// Apply will set the client certificate and root CAs on the TLS configuration.
+func (m Material) Apply(config *tls.Config) {
+	if m.Certificate != nil {
+		config.Certificates = []tls.Certificate{*m.Certificate}
+	}
+	if m.RootCAs != nil {
+		config.RootCAs = m.RootCAs
+	}
+}
+
+// parseCABundle returns a pool containing the PEM encoded certificates.
+func parseCABundle(bundle string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(bundle)) {
+		return nil, errors.New("bundle contains no certificates")
+	}
+	return pool, nil
+}
@@ -0,0 +1,103 @@
+package credential
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+// newKeyPair returns a PEM encoded self-signed certificate and private key.
+func newKeyPair(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	private := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: encoded})
+	return string(certificate), string(private)
+}
+
+func TestCipher(t *testing.T) {
+	cipher, err := NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := cipher.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.Assert(t, !strings.Contains(encrypted, "secret"), "expected ciphertext")
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, string(decrypted), "secret")
+
+	other, _ := NewCipher([]byte(strings.Repeat("x", 32)))
+	_, err = other.Decrypt(encrypted)
+	debug.Assert(t, err != nil, "expected error when decrypting with a different key")
+
+	_, err = NewCipher(make([]byte, 16))
+	debug.Assert(t, err != nil, "expected error for short key")
+}
+
+func TestApplicationValidate(t *testing.T) {
+	certificate, key := newKeyPair(t)
+	_, other := newKeyPair(t)
+	invalid := "invalid"
+
+	debug.Assert(t, Application{Name: "a", Certificate: &certificate, PrivateKey: &key}.Validate(true) == nil, "expected valid key pair")
+	debug.Assert(t, Application{Name: "a", CABundle: &certificate}.Validate(true) == nil, "expected valid ca bundle")
+	debug.Assert(t, Application{Name: "a", Certificate: &certificate}.Validate(false) == nil, "expected key to be optional")
+
+	debug.Assert(t, Application{Name: "a", Certificate: &certificate}.Validate(true) != nil, "expected key to be required")
+	debug.Assert(t, Application{Name: "a", Certificate: &certificate, PrivateKey: &other}.Validate(true) != nil, "expected mismatched key pair")
+	debug.Assert(t, Application{Name: "a", CABundle: &invalid}.Validate(true) != nil, "expected invalid ca bundle")
+	debug.Assert(t, Application{Name: "a"}.Validate(true) != nil, "expected certificate or ca bundle")
+	debug.Assert(t, Application{Certificate: &certificate, PrivateKey: &key}.Validate(true) != nil, "expected name")
+}
+
+func TestCredentialMarshalJSON(t *testing.T) {
+	key := "encrypted"
+	encoded, err := json.Marshal(Credential{Name: "a", PrivateKey: &key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	_, exists := decoded["privateKey"]
+	debug.Assert(t, !exists, "expected private key to be omitted")
+	debug.AssertEqual(t, decoded["hasPrivateKey"], any(true))
+	debug.Assert(t, !strings.Contains(string(encoded), key), "expected private key to be omitted")
+}
@@ -0,0 +1,32 @@
+package credential
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmkng/zenin/pkg/sql"
+)
+
+// CredentialRepository is a type used to interact with the credential domain database table.
+type CredentialRepository interface {
+	SelectCredential(ctx context.Context, params *SelectCredentialParams) ([]Credential, error)
+	InsertCredential(ctx context.Context, credential Credential) (int, error)
+	UpdateCredential(ctx context.Context, credential Credential) error
+	DeleteCredential(ctx context.Context, id []int) error
+}
+
+// SelectCredentialParams is a set of parameters used to narrow the scope of the `SelectCredential` repository method.
+//
+// Implements `Injectable.Inject`, so it can automatically apply suitable SQL to a `sql.Builder`.
+type SelectCredentialParams struct {
+	Id *[]int
+}
+
+// Inject implements `Injectable.Inject` for `SelectCredentialParams`.
+func (s SelectCredentialParams) Inject(builder *sql.Builder) {
+	if s.Id != nil && len(*s.Id) > 0 {
+		builder.Push(fmt.Sprintf("%v id IN (", builder.Where()))
+		builder.SpreadInt(*s.Id...)
+		builder.Push(")")
+	}
+}
@@ -0,0 +1,142 @@
+package credential
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// NewCredentialService returns a new `CredentialService`.
+func NewCredentialService(r CredentialRepository, c Cipher) CredentialService {
+	return CredentialService{Repository: r, Cipher: c}
+}
+
+// CredentialService is a service used to interact with the credential domain type.
+type CredentialService struct {
+	Repository CredentialRepository
+	Cipher     Cipher
+}
+
+// CredentialNotFoundError means that a `Credential` with the requested id does not exist.
+var CredentialNotFoundError env.Validation = env.NewValidation("Credential does not exist.")
+
+func (c CredentialService) GetCredentials(ctx context.Context) ([]Credential, error) {
+	return c.Repository.SelectCredential(ctx, nil)
+}
+
+func (c CredentialService) CreateCredential(ctx context.Context, app Application) (int, internal.TimestampValue, error) {
+	if err := app.Validate(true); err != nil {
+		return -1, internal.TimestampValue{}, err
+	}
+
+	time := internal.NewTimeValue(time.Now())
+	credential := Credential{
+		CreatedAt:   time,
+		UpdatedAt:   time,
+		Name:        app.Name,
+		Certificate: app.Certificate,
+		CABundle:    app.CABundle,
+	}
+	if app.PrivateKey != nil {
+		encrypted, err := c.Cipher.Encrypt([]byte(*app.PrivateKey))
+		if err != nil {
+			return -1, internal.TimestampValue{}, err
+		}
+		credential.PrivateKey = &encrypted
+	}
+
+	id, err := c.Repository.InsertCredential(ctx, credential)
+	if err != nil {
+		return -1, internal.TimestampValue{}, err
+	}
+
+	return id, internal.TimestampValue{Time: time}, nil
+}
+
+func (c CredentialService) UpdateCredential(ctx context.Context, id int, app Application) (internal.TimestampValue, error) {
+	if err := app.Validate(false); err != nil {
+		return internal.TimestampValue{}, err
+	}
+
+	found, err := c.Repository.SelectCredential(ctx, &SelectCredentialParams{Id: &[]int{id}})
+	if err != nil {
+		return internal.TimestampValue{}, err
+	}
+	if len(found) == 0 {
+		return internal.TimestampValue{}, CredentialNotFoundError
+	}
+	credential := found[0]
+
+	switch {
+	case app.Certificate == nil:
+		credential.PrivateKey = nil
+	case app.PrivateKey != nil:
+		encrypted, err := c.Cipher.Encrypt([]byte(*app.PrivateKey))
+		if err != nil {
+			return internal.TimestampValue{}, err
+		}
+		credential.PrivateKey = &encrypted
+	default:
+		// Keep the stored private key, but make sure it still matches the certificate.
+		if credential.PrivateKey == nil {
+			return internal.TimestampValue{}, env.NewValidation("value for field `privateKey` is required with `certificate`")
+		}
+		key, err := c.Cipher.Decrypt(*credential.PrivateKey)
+		if err != nil {
+			return internal.TimestampValue{}, err
+		}
+		if _, err := tls.X509KeyPair([]byte(*app.Certificate), key); err != nil {
+			return internal.TimestampValue{}, env.NewValidation("value for field `certificate` does not match the stored private key")
+		}
+	}
+
+	time := internal.NewTimeValue(time.Now())
+	credential.UpdatedAt = time
+	credential.Name = app.Name
+	credential.Certificate = app.Certificate
+	credential.CABundle = app.CABundle
+
+	if err := c.Repository.UpdateCredential(ctx, credential); err != nil {
+		return internal.TimestampValue{}, err
+	}
+
+	return internal.TimestampValue{Time: time}, nil
+}
+
+// GetMaterial returns the decrypted `Material` for the `Credential` with the id.
+func (c CredentialService) GetMaterial(ctx context.Context, id int) (Material, error) {
+	found, err := c.Repository.SelectCredential(ctx, &SelectCredentialParams{Id: &[]int{id}})
+	if err != nil {
+		return Material{}, err
+	}
+	if len(found) == 0 {
+		return Material{}, CredentialNotFoundError
+	}
+	credential := found[0]
+
+	var material Material
+	if credential.Certificate != nil && credential.PrivateKey != nil {
+		key, err := c.Cipher.Decrypt(*credential.PrivateKey)
+		if err != nil {
+			return Material{}, err
+		}
+		pair, err := tls.X509KeyPair([]byte(*credential.Certificate), key)
+		if err != nil {
+			return Material{}, fmt.Errorf("failed to parse key pair: %w", err)
+		}
+		material.Certificate = &pair
+	}
+	if credential.CABundle != nil {
+		pool, err := parseCABundle(*credential.CABundle)
+		if err != nil {
+			return Material{}, fmt.Errorf("failed to parse ca bundle: %w", err)
+		}
+		material.RootCAs = pool
+	}
+
+	return material, nil
+}
@@ -2,6 +2,7 @@ package env
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -60,6 +61,14 @@ func NewEnvironment() Environment {
 	if err != nil {
 		panic(fmt.Errorf("failed to generate sign secret: %w", err))
 	}
+	var secretKey Secret
+	if x := os.Getenv(secretKeyKey); x != "" {
+		secretKey = []byte(x)
+	}
+	var metricsToken Secret
+	if x := os.Getenv(metricsTokenKey); x != "" {
+		metricsToken = []byte(x)
+	}
 
 	stdoutFormat := Flat
 	switch key := strings.ToLower(os.Getenv(stdoutFormatKey)); key {
@@ -110,21 +119,69 @@ func NewEnvironment() Environment {
 	if err != nil {
 		allowInsecure = false
 	}
+	tlsCert := os.Getenv(tlsCertKey)
+	tlsKey := os.Getenv(tlsKeyKey)
+	var acmeDomains []string
+	for _, v := range strings.Split(os.Getenv(acmeDomainsKey), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			acmeDomains = append(acmeDomains, v)
+		}
+	}
+	acmeDirectory := "https://acme-v02.api.letsencrypt.org/directory"
+	if x := os.Getenv(acmeDirectoryKey); x != "" {
+		acmeDirectory = x
+	}
+	var probeWorkers uint16
+	if x, err := strconv.ParseUint(os.Getenv(probeWorkersKey), 10, 16); err == nil {
+		probeWorkers = uint16(x)
+	}
+	var eventWorkers uint16
+	if x, err := strconv.ParseUint(os.Getenv(eventWorkersKey), 10, 16); err == nil {
+		eventWorkers = uint16(x)
+	}
+	var sinkInfluxToken Secret
+	if x := os.Getenv(sinkInfluxTokenKey); x != "" {
+		sinkInfluxToken = []byte(x)
+	}
+	var sinkOTLPHeaders Secret
+	if x := os.Getenv(sinkOTLPHeadersKey); x != "" {
+		sinkOTLPHeaders = []byte(x)
+	}
+	var sinkBuffer uint16
+	if x, err := strconv.ParseUint(os.Getenv(sinkBufferKey), 10, 16); err == nil {
+		sinkBuffer = uint16(x)
+	}
 
 	return Environment{
-		Address:          address,
-		Port:             port,
-		RedirectPort:     redirect,
-		SignSecret:       signSecret,
-		StdoutFormat:     stdoutFormat,
-		StdoutTimeFormat: stdoutTimeFormat,
-		BaseDir:          baseDir,
-		PluginsDir:       pluginsDir,
-		ThemesDir:        themesDir,
-		EnableColor:      enableColor,
-		EnableDebug:      enableDebug,
-		AllowInsecure:    allowInsecure,
-		Repository:       NewRepositoryEnvironment(),
+		Address:           address,
+		Port:              port,
+		RedirectPort:      redirect,
+		TLSCert:           tlsCert,
+		TLSKey:            tlsKey,
+		ACMEDomains:       acmeDomains,
+		ACMEEmail:         os.Getenv(acmeEmailKey),
+		ACMEDirectory:     acmeDirectory,
+		ACMERootCA:        os.Getenv(acmeRootCAKey),
+		SignSecret:        signSecret,
+		SecretKey:         secretKey,
+		MetricsToken:      metricsToken,
+		StdoutFormat:      stdoutFormat,
+		StdoutTimeFormat:  stdoutTimeFormat,
+		BaseDir:           baseDir,
+		PluginsDir:        pluginsDir,
+		ThemesDir:         themesDir,
+		EnableColor:       enableColor,
+		EnableDebug:       enableDebug,
+		AllowInsecure:     allowInsecure,
+		ProbeWorkers:      probeWorkers,
+		EventWorkers:      eventWorkers,
+		SinkInfluxURL:     os.Getenv(sinkInfluxURLKey),
+		SinkInfluxToken:   sinkInfluxToken,
+		SinkOTLPURL:       os.Getenv(sinkOTLPURLKey),
+		SinkOTLPHeaders:   sinkOTLPHeaders,
+		SinkStatsDAddress: os.Getenv(sinkStatsDAddressKey),
+		SinkBuffer:        sinkBuffer,
+		Repository:        NewRepositoryEnvironment(),
 	}
 }
 
@@ -145,10 +202,31 @@ type Environment struct {
 	Port uint16
 	// A port number used to redirect HTTP requests.
 	RedirectPort uint16
+	// Path to a PEM encoded certificate file used to serve HTTPS.
+	// Reloaded when the file changes.
+	TLSCert string
+	// Path to the PEM encoded private key file for `TLSCert`.
+	TLSKey string
+	// Domains to obtain a certificate for from the ACME directory.
+	// Certificates are obtained automatically when not empty.
+	ACMEDomains []string
+	// Contact email registered with the ACME account.
+	ACMEEmail string
+	// URL of the ACME directory. Defaults to Let's Encrypt.
+	ACMEDirectory string
+	// Path to a PEM encoded certificate file trusted when connecting to the ACME directory,
+	// in addition to the system roots.
+	ACMERootCA string
 
 	// A sequence used to sign tokens.
 	// Autogenerated unless found in the environment.
 	SignSecret Secret
+	// A sequence used to derive the key that encrypts secrets at rest.
+	// If not found in the environment, a key file is created in the base directory.
+	SecretKey Secret
+	// A bearer token required to read metrics.
+	// Metrics are readable without authentication when empty.
+	MetricsToken Secret
 
 	// Controls the format used by the logging mechanism.
 	StdoutFormat LogKind
@@ -172,6 +250,27 @@ type Environment struct {
 
 	AllowInsecure bool
 
+	// Maximum number of probes that may run at once.
+	// A default is used when zero.
+	ProbeWorkers uint16
+	// Maximum number of events that may run at once.
+	// A default is used when zero.
+	EventWorkers uint16
+
+	// URL of an InfluxDB write endpoint that measurements are exported to.
+	SinkInfluxURL string
+	// A token sent to the InfluxDB write endpoint.
+	SinkInfluxToken Secret
+	// URL of an OTLP/HTTP metrics endpoint that measurements are exported to.
+	SinkOTLPURL string
+	// Comma separated `key=value` headers sent to the OTLP endpoint.
+	SinkOTLPHeaders Secret
+	// Address of a StatsD server that measurements are exported to.
+	SinkStatsDAddress string
+	// Maximum number of measurements buffered for each sink.
+	// A default is used when zero.
+	SinkBuffer uint16
+
 	Repository RepositoryEnv
 }
 
@@ -181,6 +280,16 @@ func (e Environment) Diagnose(dx *Diagnostic) {
 		dx.Error("sign secret is weak, expected >= 16 bytes")
 	}
 
+	if (e.TLSCert == "") != (e.TLSKey == "") {
+		dx.Error("must set both `ZENIN_TLS_CERT` and `ZENIN_TLS_KEY` to enable tls")
+	}
+	if e.TLSCert != "" && len(e.ACMEDomains) > 0 {
+		dx.Error("must not set `ZENIN_TLS_CERT` with `ZENIN_ACME_DOMAINS`")
+	}
+	if e.RedirectPort != 0 && e.TLSCert == "" && len(e.ACMEDomains) == 0 {
+		dx.Warn("redirect port is ignored because tls is not enabled")
+	}
+
 	vd(dx, e.BaseDir, "ZENIN_BASE_DIR", "base")
 	vd(dx, e.PluginsDir, "ZENIN_PLUGINS_DIR", "plugins")
 	vd(dx, e.ThemesDir, "ZENIN_THEMES_DIR", "themes")
@@ -215,6 +324,40 @@ func (e Environment) ReadTheme(name string) (string, error) {
 	return string(bytes), nil
 }
 
+// GetSecretKey returns the 32 byte key used to encrypt secrets at rest.
+//
+// The key is derived from `SecretKey` when set. Otherwise it is read from a key file in
+// the base directory, which is created with a random key if it does not exist.
+func (e Environment) GetSecretKey() ([]byte, error) {
+	if len(e.SecretKey) > 0 {
+		sum := sha256.Sum256(e.SecretKey)
+		return sum[:], nil
+	}
+
+	path := filepath.Join(e.BaseDir, secretKeyFile)
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("secret key file %v is invalid, expected 32 bytes", path)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read secret key file: %w", err)
+	}
+
+	key, err = GetRandomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write secret key file: %w", err)
+	}
+	Info("created secret key file", "path", path)
+
+	return key, nil
+}
+
 // GetLocalRepositoryPath returns the expected path of a local database file.
 func (e Environment) GetLocalRepositoryPath() string {
 	return filepath.Join(e.BaseDir, e.Repository.Name)
@@ -327,23 +470,43 @@ func vd(dx *Diagnostic, path, env, dir string) {
 }
 
 const (
-	addressKey          = "ZENIN_ADDRESS"
-	portKey             = "ZENIN_PORT"
-	redirectKey         = "ZENIN_REDIRECT_PORT"
-	signSecretKey       = "ZENIN_SIGN_SECRET"
-	stdoutFormatKey     = "ZENIN_STDOUT_FORMAT"
-	stdoutTimeFormatKey = "ZENIN_STDOUT_TIME_FORMAT"
-	baseDirKey          = "ZENIN_BASE_DIR"
-	pluginsDirKey       = "ZENIN_PLUGINS_DIR"
-	themesDirKey        = "ZENIN_THEMES_DIR"
-	enableColorKey      = "ZENIN_ENABLE_COLOR"
-	enableDebugKey      = "ZENIN_ENABLE_DEBUG"
-	allowInsecureKey    = "ZENIN_ALLOW_INSECURE"
-	repoKindKey         = "ZENIN_REPO_KIND"
-	repoUsernameKey     = "ZENIN_REPO_USERNAME"
-	repoPasswordKey     = "ZENIN_REPO_PASSWORD"
-	repoAddressKey      = "ZENIN_REPO_ADDRESS"
-	repoPortKey         = "ZENIN_REPO_PORT"
-	repoNameKey         = "ZENIN_REPO_NAME"
-	repoMaxConnKey      = "ZENIN_REPO_MAX_CONN"
+	addressKey           = "ZENIN_ADDRESS"
+	portKey              = "ZENIN_PORT"
+	redirectKey          = "ZENIN_REDIRECT_PORT"
+	tlsCertKey           = "ZENIN_TLS_CERT"
+	tlsKeyKey            = "ZENIN_TLS_KEY"
+	acmeDomainsKey       = "ZENIN_ACME_DOMAINS"
+	acmeEmailKey         = "ZENIN_ACME_EMAIL"
+	acmeDirectoryKey     = "ZENIN_ACME_DIRECTORY"
+	acmeRootCAKey        = "ZENIN_ACME_ROOT_CA"
+	signSecretKey        = "ZENIN_SIGN_SECRET"
+	secretKeyKey         = "ZENIN_SECRET_KEY"
+	metricsTokenKey      = "ZENIN_METRICS_TOKEN"
+	stdoutFormatKey      = "ZENIN_STDOUT_FORMAT"
+	stdoutTimeFormatKey  = "ZENIN_STDOUT_TIME_FORMAT"
+	baseDirKey           = "ZENIN_BASE_DIR"
+	pluginsDirKey        = "ZENIN_PLUGINS_DIR"
+	themesDirKey         = "ZENIN_THEMES_DIR"
+	enableColorKey       = "ZENIN_ENABLE_COLOR"
+	enableDebugKey       = "ZENIN_ENABLE_DEBUG"
+	allowInsecureKey     = "ZENIN_ALLOW_INSECURE"
+	repoKindKey          = "ZENIN_REPO_KIND"
+	repoUsernameKey      = "ZENIN_REPO_USERNAME"
+	repoPasswordKey      = "ZENIN_REPO_PASSWORD"
+	repoAddressKey       = "ZENIN_REPO_ADDRESS"
+	repoPortKey          = "ZENIN_REPO_PORT"
+	repoNameKey          = "ZENIN_REPO_NAME"
+	repoMaxConnKey       = "ZENIN_REPO_MAX_CONN"
+	probeWorkersKey      = "ZENIN_PROBE_WORKERS"
+	eventWorkersKey      = "ZENIN_EVENT_WORKERS"
+	sinkInfluxURLKey     = "ZENIN_SINK_INFLUX_URL"
+	sinkInfluxTokenKey   = "ZENIN_SINK_INFLUX_TOKEN"
+	sinkOTLPURLKey       = "ZENIN_SINK_OTLP_URL"
+	sinkOTLPHeadersKey   = "ZENIN_SINK_OTLP_HEADERS"
+	sinkStatsDAddressKey = "ZENIN_SINK_STATSD_ADDRESS"
+	sinkBufferKey        = "ZENIN_SINK_BUFFER"
 )
+
+// secretKeyFile is the name of the key file created in the base directory
+// when no secret key is found in the environment.
+const secretKeyFile = "secret.key"
@@ -0,0 +1,150 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// Mode determines what happens to a monitor during a maintenance window.
+type Mode string
+
+const (
+	// Pause stops polling the monitor.
+	Pause Mode = "PAUSE"
+	// Suppress continues polling the monitor, but events are not started.
+	Suppress Mode = "SUPPRESS"
+)
+
+// Maintenance is the maintenance domain type.
+//
+// A maintenance window is either one-off, lasting from `StartsAt` until `EndsAt`, or recurring,
+// lasting `Duration` seconds from each occurrence of `Recurrence` between `StartsAt` and `EndsAt`.
+type Maintenance struct {
+	Id          *int               `json:"id" db:"maintenance_id"`
+	CreatedAt   internal.TimeValue `json:"createdAt" db:"created_at"`
+	UpdatedAt   internal.TimeValue `json:"updatedAt" db:"updated_at"`
+	Name        string             `json:"name" db:"name"`
+	Description *string            `json:"description" db:"description"`
+	Mode        Mode               `json:"mode" db:"mode"`
+	StartsAt    internal.TimeValue `json:"startsAt" db:"starts_at"`
+	// EndsAt is the end of a one-off window. Recurring windows do not recur after this time,
+	// or recur indefinitely when this is nil.
+	EndsAt *internal.TimeValue `json:"endsAt" db:"ends_at"`
+	// Recurrence is a cron expression or an RRULE, such as `FREQ=WEEKLY;BYDAY=SA;BYHOUR=2`.
+	//
+	// An RRULE starts on `StartsAt`, and uses its time of day when `BYHOUR` or `BYMINUTE` are omitted.
+	Recurrence *string `json:"recurrence" db:"recurrence"`
+	// Timezone is the IANA time zone `Recurrence` is evaluated in. Defaults to UTC.
+	Timezone *string `json:"timezone" db:"timezone"`
+	// Duration is the number of seconds each occurrence of a recurring window lasts.
+	Duration *int `json:"duration" db:"duration"`
+	// MonitorId is the list of monitors the window applies to.
+	MonitorId []int `json:"monitorId" db:"-"`
+}
+
+// Validate will return an error if the `Maintenance` is in an invalid state.
+func (m Maintenance) Validate() error {
+	errors := []string{}
+
+	if strings.TrimSpace(m.Name) == "" {
+		errors = append(errors, "value for field `name` is required")
+	}
+	if m.Mode != Pause && m.Mode != Suppress {
+		errors = append(errors, "value for field `mode` must be `PAUSE` or `SUPPRESS`")
+	}
+	if m.StartsAt.Time().IsZero() {
+		errors = append(errors, "value for field `startsAt` is required")
+	}
+	if m.EndsAt != nil && !m.EndsAt.Time().After(m.StartsAt.Time()) {
+		errors = append(errors, "value for field `endsAt` must be after `startsAt`")
+	}
+	if len(m.MonitorId) == 0 {
+		errors = append(errors, "value for field `monitorId` must contain at least one monitor")
+	}
+
+	if m.Recurrence == nil {
+		if m.EndsAt == nil {
+			errors = append(errors, "value for field `endsAt` is required without `recurrence`")
+		}
+		if m.Duration != nil {
+			errors = append(errors, "value for field `recurrence` is required with `duration`")
+		}
+		if m.Timezone != nil {
+			errors = append(errors, "value for field `recurrence` is required with `timezone`")
+		}
+	} else {
+		if _, err := m.recurrence(); err != nil {
+			errors = append(errors, fmt.Sprintf("value for field `recurrence` is invalid: %v", err))
+		}
+		if m.Duration == nil {
+			errors = append(errors, "value for field `duration` is required with `recurrence`")
+		} else if *m.Duration <= 0 {
+			errors = append(errors, "value for field `duration` must be greater than zero")
+		}
+	}
+
+	if len(errors) > 0 {
+		return env.NewValidation(errors...)
+	}
+	return nil
+}
+
+// IsActive returns true if the window is active at the time.
+func (m Maintenance) IsActive(t time.Time) bool {
+	start := m.StartsAt.Time()
+	if t.Before(start) || m.EndsAt != nil && !t.Before(m.EndsAt.Time()) {
+		return false
+	}
+	if m.Recurrence == nil {
+		return true
+	}
+
+	rule, err := m.recurrence()
+	if err != nil || m.Duration == nil {
+		return false
+	}
+	from := t.Add(-time.Duration(*m.Duration) * time.Second)
+	if from.Before(start) {
+		// Occurrences before the start of the window are ignored.
+		from = start.Add(-time.Nanosecond)
+	}
+	return rule.started(from, t)
+}
+
+// Applies returns true if the window applies to the monitor.
+func (m Maintenance) Applies(id int) bool {
+	for _, v := range m.MonitorId {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the window that is active for the monitor at the time, or nil.
+//
+// When several windows are active, a `Pause` window is preferred over a `Suppress` window.
+func Find(windows []Maintenance, id int, t time.Time) *Maintenance {
+	var found *Maintenance
+	for i, v := range windows {
+		if !v.Applies(id) || !v.IsActive(t) {
+			continue
+		}
+		if found == nil || v.Mode == Pause {
+			found = &windows[i]
+		}
+		if found.Mode == Pause {
+			break
+		}
+	}
+	return found
+}
+
+// MaintenanceMessage is used to deliver the list of all maintenance windows to the distributor.
+type MaintenanceMessage struct {
+	Windows []Maintenance
+}
@@ -0,0 +1,90 @@
+package maintenance
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestMaintenanceIsActive(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.April, day, hour, minute, 0, 0, time.UTC)
+	}
+	value := func(t time.Time) *internal.TimeValue { v := internal.NewTimeValue(t); return &v }
+	text := func(v string) *string { return &v }
+	seconds := func(v int) *int { return &v }
+
+	// Monday, April 1st 2024.
+	start := internal.NewTimeValue(at(1, 0, 0))
+	oneOff := Maintenance{StartsAt: start, EndsAt: value(at(1, 2, 0))}
+	weekly := Maintenance{StartsAt: start, Recurrence: text("0 2 * * sat"), Duration: seconds(7200)}
+	rule := Maintenance{StartsAt: start, Recurrence: text("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;BYHOUR=22;BYMINUTE=30"), Duration: seconds(3600)}
+	berlin := Maintenance{StartsAt: start, Recurrence: text("FREQ=DAILY;BYHOUR=3"), Timezone: text("Europe/Berlin"), Duration: seconds(1800)}
+	ending := Maintenance{StartsAt: start, EndsAt: value(at(10, 0, 0)), Recurrence: text("@daily"), Duration: seconds(600)}
+
+	cases := []struct {
+		window Maintenance
+		time   time.Time
+		expect bool
+	}{
+		{oneOff, at(1, 1, 0), true},
+		{oneOff, at(1, 2, 0), false},
+		{oneOff, at(1, 0, 0).Add(-time.Second), false},
+		{weekly, at(6, 2, 0), true},
+		{weekly, at(6, 3, 59), true},
+		{weekly, at(6, 4, 0), false},
+		{weekly, at(5, 2, 30), false},
+		{rule, at(2, 22, 45), true},
+		{rule, at(4, 23, 29), true},
+		{rule, at(9, 22, 45), false},
+		{rule, at(16, 23, 0), true},
+		{rule, at(2, 21, 0), false},
+		{berlin, at(2, 1, 15), true},
+		{berlin, at(2, 3, 15), false},
+		{ending, at(9, 0, 5), true},
+		{ending, at(10, 0, 5), false},
+	}
+
+	for i, v := range cases {
+		debug.Assert(t, v.window.IsActive(v.time) == v.expect, "unexpected result for case", strconv.Itoa(i))
+	}
+}
+
+func TestMaintenanceValidate(t *testing.T) {
+	text := func(v string) *string { return &v }
+	seconds := func(v int) *int { return &v }
+	start := internal.NewTimeValue(time.Now())
+
+	valid := Maintenance{Name: "Upgrade", Mode: Suppress, StartsAt: start, Recurrence: text("@weekly"), Duration: seconds(60), MonitorId: []int{1}}
+	debug.Assert(t, valid.Validate() == nil, "expected valid window")
+
+	for _, v := range []Maintenance{
+		{Name: "Upgrade", Mode: Pause, StartsAt: start, MonitorId: []int{1}},
+		{Name: "Upgrade", Mode: "STOP", StartsAt: start, Recurrence: text("@weekly"), Duration: seconds(60), MonitorId: []int{1}},
+		{Name: "Upgrade", Mode: Pause, StartsAt: start, Recurrence: text("@weekly"), MonitorId: []int{1}},
+		{Name: "Upgrade", Mode: Pause, StartsAt: start, Recurrence: text("FREQ=YEARLY"), Duration: seconds(60), MonitorId: []int{1}},
+		{Name: "Upgrade", Mode: Pause, StartsAt: start, Recurrence: text("FREQ=DAILY;COUNT=3"), Duration: seconds(60), MonitorId: []int{1}},
+		{Name: "Upgrade", Mode: Pause, StartsAt: start, Recurrence: text("@weekly"), Duration: seconds(60)},
+	} {
+		debug.Assert(t, v.Validate() != nil, "expected invalid window")
+	}
+}
+
+func TestFind(t *testing.T) {
+	now := time.Now()
+	start := internal.NewTimeValue(now.Add(-time.Minute))
+	end := internal.NewTimeValue(now.Add(time.Minute))
+	id := 1
+	windows := []Maintenance{
+		{Id: &id, Mode: Suppress, StartsAt: start, EndsAt: &end, MonitorId: []int{1, 2}},
+		{Id: &id, Mode: Pause, StartsAt: start, EndsAt: &end, MonitorId: []int{2}},
+	}
+
+	debug.AssertEqual(t, Find(windows, 1, now).Mode, Suppress)
+	debug.AssertEqual(t, Find(windows, 2, now).Mode, Pause)
+	debug.Assert(t, Find(windows, 3, now) == nil, "expected no window")
+	debug.Assert(t, Find(windows, 1, now.Add(time.Hour)) == nil, "expected no window")
+}
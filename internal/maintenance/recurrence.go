@@ -0,0 +1,243 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmkng/zenin/pkg/cron"
+)
+
+// recurrence determines when the occurrences of a recurring window start.
+type recurrence interface {
+	// started returns true if an occurrence started after `from`, and not after `to`.
+	started(from, to time.Time) bool
+}
+
+// recurrence returns the parsed `Recurrence` of the window.
+func (m Maintenance) recurrence() (recurrence, error) {
+	if m.Recurrence == nil {
+		return nil, errors.New("window is not recurring")
+	}
+	value := strings.TrimSpace(*m.Recurrence)
+	upper := strings.ToUpper(value)
+	if strings.HasPrefix(upper, "RRULE:") || strings.Contains(upper, "FREQ=") {
+		return parseRule(upper, m.StartsAt.Time(), m.Timezone)
+	}
+
+	schedule, err := cron.Parse(value, m.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return cronRecurrence{schedule: schedule}, nil
+}
+
+// cronRecurrence is a recurrence described by a cron expression.
+type cronRecurrence struct {
+	schedule cron.Schedule
+}
+
+func (c cronRecurrence) started(from, to time.Time) bool {
+	next := c.schedule.Next(from)
+	return !next.IsZero() && !next.After(to)
+}
+
+type frequency string
+
+const (
+	daily   frequency = "DAILY"
+	weekly  frequency = "WEEKLY"
+	monthly frequency = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// rule is a recurrence described by an RRULE (RFC 5545).
+//
+// Supports the `FREQ` (`DAILY`, `WEEKLY` or `MONTHLY`), `INTERVAL`, `BYDAY` (without ordinals),
+// `BYMONTHDAY`, `BYHOUR`, `BYMINUTE` and `UNTIL` parts.
+type rule struct {
+	frequency frequency
+	interval  int
+	days      map[time.Weekday]bool
+	monthDays map[int]bool
+	hours     []int
+	minutes   []int
+	until     *time.Time
+	// start is the first occurrence (DTSTART), in `location`.
+	start    time.Time
+	location *time.Location
+}
+
+// parseRule returns the `rule` for the uppercase RRULE, starting at `start`.
+func parseRule(value string, start time.Time, timezone *string) (rule, error) {
+	r := rule{interval: 1, location: time.UTC}
+	if timezone != nil {
+		location, err := time.LoadLocation(*timezone)
+		if err != nil {
+			return rule{}, fmt.Errorf("unknown time zone `%v`", *timezone)
+		}
+		r.location = location
+	}
+	r.start = start.In(r.location)
+
+	for _, part := range strings.Split(strings.TrimPrefix(value, "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule{}, fmt.Errorf("invalid rule part `%v`", part)
+		}
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch frequency(value) {
+			case daily, weekly, monthly:
+				r.frequency = frequency(value)
+			default:
+				return rule{}, fmt.Errorf("unsupported frequency `%v`, expected DAILY, WEEKLY or MONTHLY", value)
+			}
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval <= 0 {
+				return rule{}, fmt.Errorf("invalid interval `%v`", value)
+			}
+		case "BYDAY":
+			r.days = map[time.Weekday]bool{}
+			for _, v := range strings.Split(value, ",") {
+				day, ok := weekdays[v]
+				if !ok {
+					return rule{}, fmt.Errorf("invalid day `%v`", v)
+				}
+				r.days[day] = true
+			}
+		case "BYMONTHDAY":
+			days, err := parseRuleList(value, key, 1, 31)
+			if err != nil {
+				return rule{}, err
+			}
+			r.monthDays = map[int]bool{}
+			for _, v := range days {
+				r.monthDays[v] = true
+			}
+		case "BYHOUR":
+			if r.hours, err = parseRuleList(value, key, 0, 23); err != nil {
+				return rule{}, err
+			}
+		case "BYMINUTE":
+			if r.minutes, err = parseRuleList(value, key, 0, 59); err != nil {
+				return rule{}, err
+			}
+		case "UNTIL":
+			until, err := time.Parse("20060102T150405Z", value)
+			if err != nil {
+				if until, err = time.ParseInLocation("20060102", value, r.location); err != nil {
+					return rule{}, fmt.Errorf("invalid until `%v`", value)
+				}
+				until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			r.until = &until
+		default:
+			return rule{}, fmt.Errorf("unsupported rule part `%v`", key)
+		}
+	}
+	if r.frequency == "" {
+		return rule{}, errors.New("rule part `FREQ` is required")
+	}
+	if r.hours == nil {
+		r.hours = []int{r.start.Hour()}
+	}
+	if r.minutes == nil {
+		r.minutes = []int{r.start.Minute()}
+	}
+
+	return r, nil
+}
+
+// parseRuleList returns the numbers in a comma separated rule part value.
+func parseRuleList(value, key string, min, max int) ([]int, error) {
+	result := []int{}
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min || n > max {
+			return nil, fmt.Errorf("invalid value `%v` in `%v`, expected %v-%v", v, key, min, max)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func (r rule) started(from, to time.Time) bool {
+	first, last := from.In(r.location), to.In(r.location)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, r.location)
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !r.matchDay(day) {
+			continue
+		}
+		for _, hour := range r.hours {
+			for _, minute := range r.minutes {
+				at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, r.location)
+				if at.After(from) && !at.After(to) && !at.Before(r.start) && (r.until == nil || !at.After(*r.until)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// matchDay returns true if an occurrence may start on the day.
+func (r rule) matchDay(day time.Time) bool {
+	switch r.frequency {
+	case daily:
+		if civilDays(r.start, day)%r.interval != 0 {
+			return false
+		}
+		if r.days != nil && !r.days[day.Weekday()] {
+			return false
+		}
+		return r.monthDays == nil || r.monthDays[day.Day()]
+	case weekly:
+		// Weeks start on Monday.
+		monday := func(t time.Time) time.Time { return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7)) }
+		if civilDays(monday(r.start), monday(day))/7%r.interval != 0 {
+			return false
+		}
+		if r.days != nil {
+			return r.days[day.Weekday()]
+		}
+		return day.Weekday() == r.start.Weekday()
+	case monthly:
+		months := (day.Year()-r.start.Year())*12 + int(day.Month()-r.start.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+		if r.monthDays != nil && !r.monthDays[day.Day()] {
+			return false
+		}
+		if r.days != nil {
+			return r.days[day.Weekday()]
+		}
+		return r.monthDays != nil || day.Day() == r.start.Day()
+	}
+	return false
+}
+
+// civilDays returns the number of calendar days from `a` to `b`.
+func civilDays(a, b time.Time) int {
+	x := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	y := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(y.Sub(x).Hours() / 24)
+}
@@ -0,0 +1,32 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmkng/zenin/pkg/sql"
+)
+
+// MaintenanceRepository is a type used to interact with the maintenance domain database tables.
+type MaintenanceRepository interface {
+	SelectMaintenance(ctx context.Context, params *SelectMaintenanceParams) ([]Maintenance, error)
+	InsertMaintenance(ctx context.Context, maintenance Maintenance) (int, error)
+	UpdateMaintenance(ctx context.Context, maintenance Maintenance) error
+	DeleteMaintenance(ctx context.Context, id []int) error
+}
+
+// SelectMaintenanceParams is a set of parameters used to narrow the scope of the `SelectMaintenance` repository method.
+//
+// Implements `Injectable.Inject`, so it can automatically apply suitable SQL to a `sql.Builder`.
+type SelectMaintenanceParams struct {
+	Id *[]int
+}
+
+// Inject implements `Injectable.Inject` for `SelectMaintenanceParams`.
+func (s SelectMaintenanceParams) Inject(builder *sql.Builder) {
+	if s.Id != nil && len(*s.Id) > 0 {
+		builder.Push(fmt.Sprintf("%v id IN (", builder.Where()))
+		builder.SpreadInt(*s.Id...)
+		builder.Push(")")
+	}
+}
@@ -0,0 +1,84 @@
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// NewMaintenanceService returns a new `MaintenanceService`.
+func NewMaintenanceService(r MaintenanceRepository, d chan<- any) MaintenanceService {
+	return MaintenanceService{Repository: r, Distributor: d}
+}
+
+// MaintenanceService is a service used to interact with the maintenance domain type.
+type MaintenanceService struct {
+	Repository  MaintenanceRepository
+	Distributor chan<- any
+}
+
+// MaintenanceNotFoundError means that a `Maintenance` with the requested id does not exist.
+var MaintenanceNotFoundError env.Validation = env.NewValidation("Maintenance window does not exist.")
+
+func (m MaintenanceService) GetMaintenance(ctx context.Context) ([]Maintenance, error) {
+	return m.Repository.SelectMaintenance(ctx, nil)
+}
+
+func (m MaintenanceService) CreateMaintenance(ctx context.Context, maintenance Maintenance) (int, internal.TimestampValue, error) {
+	if err := maintenance.Validate(); err != nil {
+		return -1, internal.TimestampValue{}, err
+	}
+
+	time := internal.NewTimeValue(time.Now())
+	maintenance.CreatedAt = time
+	maintenance.UpdatedAt = time
+	id, err := m.Repository.InsertMaintenance(ctx, maintenance)
+	if err != nil {
+		return -1, internal.TimestampValue{}, err
+	}
+
+	return id, internal.TimestampValue{Time: time}, m.Distribute(ctx)
+}
+
+func (m MaintenanceService) UpdateMaintenance(ctx context.Context, id int, maintenance Maintenance) (internal.TimestampValue, error) {
+	if err := maintenance.Validate(); err != nil {
+		return internal.TimestampValue{}, err
+	}
+
+	found, err := m.Repository.SelectMaintenance(ctx, &SelectMaintenanceParams{Id: &[]int{id}})
+	if err != nil {
+		return internal.TimestampValue{}, err
+	}
+	if len(found) == 0 {
+		return internal.TimestampValue{}, MaintenanceNotFoundError
+	}
+
+	time := internal.NewTimeValue(time.Now())
+	maintenance.Id = &id
+	maintenance.CreatedAt = found[0].CreatedAt
+	maintenance.UpdatedAt = time
+	if err := m.Repository.UpdateMaintenance(ctx, maintenance); err != nil {
+		return internal.TimestampValue{}, err
+	}
+
+	return internal.TimestampValue{Time: time}, m.Distribute(ctx)
+}
+
+func (m MaintenanceService) DeleteMaintenance(ctx context.Context, id []int) error {
+	if err := m.Repository.DeleteMaintenance(ctx, id); err != nil {
+		return err
+	}
+	return m.Distribute(ctx)
+}
+
+// Distribute will send all maintenance windows to the distributor.
+func (m MaintenanceService) Distribute(ctx context.Context) error {
+	windows, err := m.Repository.SelectMaintenance(ctx, nil)
+	if err != nil {
+		return err
+	}
+	m.Distributor <- MaintenanceMessage{Windows: windows}
+	return nil
+}
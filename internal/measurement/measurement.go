@@ -13,6 +13,8 @@ const (
 	Ok   ProbeState = "OK"
 	Warn ProbeState = "WARN"
 	Dead ProbeState = "DEAD"
+	// Unreachable is recorded instead of `Dead` when a parent monitor is down.
+	Unreachable ProbeState = "UNREACHABLE"
 )
 
 // Measurement is the measurement domain type.
@@ -22,6 +24,13 @@ type Measurement struct {
 	UpdatedAt internal.TimeValue `json:"updatedAt" db:"updated_at"`
 	MonitorId *int               `json:"monitorId" db:"measurement_monitor_id"`
 	Duration  float64            `json:"duration" db:"duration"`
+	// RetryAttempt is the number of the retry attempt when the measurement is a failure
+	// that is not yet confirmed, or nil.
+	RetryAttempt *int `json:"retryAttempt" db:"retry_attempt"`
+	// Flapping is true if the monitor was flapping when the measurement was taken.
+	Flapping bool `json:"flapping" db:"flapping"`
+	// Maintenance is true if the measurement was taken during a maintenance window.
+	Maintenance bool `json:"maintenance" db:"maintenance"`
 
 	Span
 }
@@ -34,6 +43,8 @@ func NewSpan() Span {
 		Certificates: []Certificate{},
 		HTTPFields:   HTTPFields{},
 		ICMPFields:   ICMPFields{},
+		DNSFields:    DNSFields{},
+		MetricFields: MetricFields{},
 		PluginFields: PluginFields{},
 	}
 }
@@ -43,7 +54,11 @@ type ProbeKind string
 const (
 	HTTP   ProbeKind = "HTTP"
 	TCP    ProbeKind = "TCP"
+	UDP    ProbeKind = "UDP"
 	ICMP   ProbeKind = "ICMP"
+	DNS    ProbeKind = "DNS"
+	Metric ProbeKind = "METRIC"
+	Push   ProbeKind = "PUSH"
 	Plugin ProbeKind = "PLUGIN"
 )
 
@@ -55,8 +70,16 @@ func ProbeKindFromString(value string) (ProbeKind, error) {
 		return HTTP, nil
 	case "tcp":
 		return TCP, nil
+	case "udp":
+		return UDP, nil
 	case "icmp":
 		return ICMP, nil
+	case "dns":
+		return DNS, nil
+	case "metric":
+		return Metric, nil
+	case "push":
+		return Push, nil
 	case "plugin":
 		return Plugin, nil
 	default:
@@ -73,6 +96,8 @@ type Span struct {
 
 	HTTPFields
 	ICMPFields
+	DNSFields
+	MetricFields
 	PluginFields
 }
 
@@ -100,6 +125,19 @@ type HTTPFields struct {
 	HTTPStatusCode      *int                    `json:"httpStatusCode" db:"http_status_code"`
 	HTTPResponseHeaders *internal.PairListValue `json:"httpResponseHeaders" db:"http_response_headers"`
 	HTTPResponseBody    *string                 `json:"httpResponseBody" db:"http_response_body"`
+	// HTTPRedirects is the chain of redirects followed, formatted as "<status code> <location>".
+	HTTPRedirects internal.ArrayValue `json:"httpRedirects" db:"http_redirects"`
+
+	// The following fields describe the phases of the request in milliseconds.
+	// A field is nil if the phase did not occur, such as DNS lookup for an IP address.
+
+	HTTPDNSDuration     *float64 `json:"httpDnsDuration" db:"http_dns_duration"`
+	HTTPConnectDuration *float64 `json:"httpConnectDuration" db:"http_connect_duration"`
+	HTTPTLSDuration     *float64 `json:"httpTlsDuration" db:"http_tls_duration"`
+	// HTTPFirstByteDuration is the time between writing the request and receiving the first response byte.
+	HTTPFirstByteDuration *float64 `json:"httpFirstByteDuration" db:"http_first_byte_duration"`
+	// HTTPTransferDuration is the time between receiving the first response byte and reading the full body.
+	HTTPTransferDuration *float64 `json:"httpTransferDuration" db:"http_transfer_duration"`
 }
 
 type ICMPFields struct {
@@ -110,6 +148,20 @@ type ICMPFields struct {
 	ICMPMaxRTT     *float64 `json:"icmpMaxRtt" db:"icmp_max_rtt"`
 }
 
+type DNSFields struct {
+	// DNSAnswers is the set of answers returned by the resolver, formatted as strings.
+	DNSAnswers internal.ArrayValue `json:"dnsAnswers" db:"dns_answers"`
+	// DNSRTT is the round trip time of the query in milliseconds.
+	DNSRTT *float64 `json:"dnsRtt" db:"dns_rtt"`
+}
+
+type MetricFields struct {
+	// MetricValue is the value of the selected metric, after aggregation.
+	//
+	// Nil if no value was selected, or the value is not a finite number.
+	MetricValue *float64 `json:"metricValue" db:"metric_value"`
+}
+
 type PluginFields struct {
 	PluginExitCode *int    `json:"pluginExitCode" db:"plugin_exit_code"`
 	PluginStdout   *string `json:"pluginStdout" db:"plugin_stdout"`
@@ -9,4 +9,7 @@ type MeasurementRepository interface {
 	InsertMeasurement(ctx context.Context, measurement Measurement) (int, error)
 	SelectCertificate(ctx context.Context, id int) ([]Certificate, error)
 	DeleteMeasurement(ctx context.Context, id []int) error
+	// SelectLastState returns the state of the most recent confirmed measurement for the monitor,
+	// ignoring `Unreachable` measurements and those taken during maintenance, or nil if there is none.
+	SelectLastState(ctx context.Context, id int) (*ProbeState, error)
 }
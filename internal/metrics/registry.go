@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kind is the type of a metric family.
+type Kind string
+
+const (
+	Gauge     Kind = "gauge"
+	Counter   Kind = "counter"
+	Histogram Kind = "histogram"
+)
+
+// ContentType is the content type of the text written by `Registry.Write`.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// NewRegistry returns a new empty `Registry`.
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]*family{}}
+}
+
+// Registry holds metric families in memory, and writes them in the Prometheus
+// text exposition format.
+// Safe for concurrent use.
+//
+// Labels are given as alternating keys and values, in the same order for every
+// series of a family.
+type Registry struct {
+	mutex    sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    Kind
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	labels []string
+	value  float64
+	// Histogram only.
+	counts []uint64
+	count  uint64
+}
+
+// Register will add a metric family.
+// Histograms count observations into the buckets, which must be sorted.
+func (r *Registry) Register(name string, help string, kind Kind, buckets ...float64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.families[name] = &family{name: name, help: help, kind: kind, buckets: buckets, series: map[string]*series{}}
+}
+
+// Set will set the value of a gauge or counter series.
+func (r *Registry) Set(name string, value float64, labels ...string) {
+	r.update(name, labels, func(s *series) { s.value = value })
+}
+
+// Add will add to the value of a gauge or counter series.
+func (r *Registry) Add(name string, delta float64, labels ...string) {
+	r.update(name, labels, func(s *series) { s.value += delta })
+}
+
+// Inc will add one to the value of a gauge or counter series.
+func (r *Registry) Inc(name string, labels ...string) {
+	r.Add(name, 1, labels...)
+}
+
+// Observe will count a value in a histogram series.
+func (r *Registry) Observe(name string, value float64, labels ...string) {
+	r.update(name, labels, func(s *series) {
+		s.value += value
+		s.count++
+		for i, v := range r.families[name].buckets {
+			if value <= v {
+				s.counts[i]++
+			}
+		}
+	})
+}
+
+// Delete will remove every series with the label set to the value.
+func (r *Registry) Delete(label string, value string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, f := range r.families {
+		for key, s := range f.series {
+			for i := 0; i+1 < len(s.labels); i += 2 {
+				if s.labels[i] == label && s.labels[i+1] == value {
+					delete(f.series, key)
+					break
+				}
+			}
+		}
+	}
+}
+
+func (r *Registry) update(name string, labels []string, update func(*series)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	f, ok := r.families[name]
+	if !ok {
+		panic(fmt.Sprintf("metric %v is not registered", name))
+	}
+	key := strings.Join(labels, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: slices.Clone(labels), counts: make([]uint64, len(f.buckets))}
+		f.series[key] = s
+	}
+	update(s)
+}
+
+// Write will write every metric family to the writer, sorted by name.
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for k := range r.families {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	b := bufio.NewWriter(w)
+	for _, name := range names {
+		f := r.families[name]
+		if len(f.series) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "# HELP %v %v\n", f.name, escape(f.help, false))
+		fmt.Fprintf(b, "# TYPE %v %v\n", f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			s := f.series[k]
+			if f.kind != Histogram {
+				writeSample(b, f.name, s.labels, s.value)
+				continue
+			}
+			for i, v := range f.buckets {
+				writeSample(b, f.name+"_bucket", append(slices.Clone(s.labels), "le", format(v)), float64(s.counts[i]))
+			}
+			writeSample(b, f.name+"_bucket", append(slices.Clone(s.labels), "le", "+Inf"), float64(s.count))
+			writeSample(b, f.name+"_sum", s.labels, s.value)
+			writeSample(b, f.name+"_count", s.labels, float64(s.count))
+		}
+	}
+	return b.Flush()
+}
+
+func writeSample(w io.Writer, name string, labels []string, value float64) {
+	io.WriteString(w, name)
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf(`%v="%v"`, labels[i], escape(labels[i+1], true)))
+		}
+		io.WriteString(w, "{"+strings.Join(pairs, ",")+"}")
+	}
+	io.WriteString(w, " "+format(value)+"\n")
+}
+
+func format(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escape will escape backslashes and line feeds, and double quotes in label values.
+func escape(value string, quote bool) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	if quote {
+		value = strings.ReplaceAll(value, `"`, `\"`)
+	}
+	return value
+}
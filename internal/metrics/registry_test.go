@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	r.Register("test_gauge", "A gauge.", Gauge)
+	r.Register("test_total", "A counter.", Counter)
+	r.Register("test_seconds", "A histogram.", Histogram, 0.1, 1)
+	r.Register("test_empty", "Never set.", Gauge)
+
+	r.Set("test_gauge", 2.5, "name", "a \"quoted\"\nname")
+	r.Inc("test_total", "result", "ok")
+	r.Inc("test_total", "result", "ok")
+	r.Observe("test_seconds", 0.05)
+	r.Observe("test_seconds", 0.5)
+	r.Observe("test_seconds", 5)
+
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	expect := `# HELP test_gauge A gauge.
+# TYPE test_gauge gauge
+test_gauge{name="a \"quoted\"\nname"} 2.5
+# HELP test_seconds A histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{le="0.1"} 1
+test_seconds_bucket{le="1"} 2
+test_seconds_bucket{le="+Inf"} 3
+test_seconds_sum 5.55
+test_seconds_count 3
+# HELP test_total A counter.
+# TYPE test_total counter
+test_total{result="ok"} 2
+`
+	debug.AssertEqual(t, b.String(), expect)
+}
+
+func TestRegistryDelete(t *testing.T) {
+	r := NewRegistry()
+	r.Register("test_gauge", "A gauge.", Gauge)
+	r.Set("test_gauge", 1, "monitor_id", "1")
+	r.Set("test_gauge", 1, "monitor_id", "2")
+
+	r.Delete("monitor_id", "1")
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	debug.Assert(t, !strings.Contains(b.String(), `monitor_id="1"`), "expected series to be deleted")
+	debug.Assert(t, strings.Contains(b.String(), `monitor_id="2"`), "expected other series to be kept")
+}
+
+func TestRecordMeasurement(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	id, code, in, out, rtt := 7, 503, 3, 4, 20.0
+	m := measurement.Measurement{
+		MonitorId: &id,
+		Duration:  250,
+		Span: measurement.Span{
+			State:        measurement.Dead,
+			HTTPFields:   measurement.HTTPFields{HTTPStatusCode: &code},
+			ICMPFields:   measurement.ICMPFields{ICMPPacketsIn: &in, ICMPPacketsOut: &out, ICMPAvgRTT: &rtt},
+			Certificates: []measurement.Certificate{{NotAfter: internal.NewTimeValue(now.AddDate(0, 0, 30))}},
+		},
+	}
+
+	r := newDefault()
+	r.RecordMeasurement("api", m, now)
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	output := b.String()
+	for _, v := range []string{
+		`zenin_monitor_state{monitor_id="7",monitor_name="api",state="DEAD"} 1`,
+		`zenin_monitor_state{monitor_id="7",monitor_name="api",state="OK"} 0`,
+		`zenin_monitor_last_duration_seconds{monitor_id="7",monitor_name="api"} 0.25`,
+		`zenin_monitor_duration_seconds_bucket{monitor_id="7",monitor_name="api",le="0.25"} 1`,
+		`zenin_monitor_http_status_code{monitor_id="7",monitor_name="api"} 503`,
+		`zenin_monitor_icmp_rtt_seconds{monitor_id="7",monitor_name="api"} 0.02`,
+		`zenin_monitor_icmp_packet_loss_ratio{monitor_id="7",monitor_name="api"} 0.25`,
+		`zenin_monitor_certificate_expiry_days{monitor_id="7",monitor_name="api"} 30`,
+	} {
+		debug.Assert(t, strings.Contains(output, v), "expected output to contain "+v)
+	}
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// Names of the metric families registered in `Default`.
+const (
+	MonitorState             = "zenin_monitor_state"
+	MonitorMeasurements      = "zenin_monitor_measurements_total"
+	MonitorLastDuration      = "zenin_monitor_last_duration_seconds"
+	MonitorDuration          = "zenin_monitor_duration_seconds"
+	MonitorHTTPStatusCode    = "zenin_monitor_http_status_code"
+	MonitorICMPRTT           = "zenin_monitor_icmp_rtt_seconds"
+	MonitorICMPPacketLoss    = "zenin_monitor_icmp_packet_loss_ratio"
+	MonitorCertificateExpiry = "zenin_monitor_certificate_expiry_days"
+
+	DistributorMonitors      = "zenin_distributor_monitors"
+	DistributorSubscribers   = "zenin_distributor_subscribers"
+	DistributorQueueDepth    = "zenin_distributor_queue_depth"
+	DistributorQueueCapacity = "zenin_distributor_queue_capacity"
+	DistributorActive        = "zenin_distributor_active_jobs"
+	DistributorDropped       = "zenin_distributor_dropped_jobs_total"
+
+	PluginExecutions = "zenin_plugin_executions_total"
+	RepositoryErrors = "zenin_repository_errors_total"
+	SinkRecords      = "zenin_sink_records_total"
+)
+
+// DurationBuckets are the upper bounds of the `MonitorDuration` histogram in seconds.
+var DurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Default is the registry served by the metrics endpoint.
+var Default = newDefault()
+
+func newDefault() *Registry {
+	r := NewRegistry()
+	r.Register(MonitorState, "State of the last confirmed measurement, 1 for the current state.", Gauge)
+	r.Register(MonitorMeasurements, "Number of confirmed measurements by state.", Counter)
+	r.Register(MonitorLastDuration, "Duration of the last measurement.", Gauge)
+	r.Register(MonitorDuration, "Duration of measurements.", Histogram, DurationBuckets...)
+	r.Register(MonitorHTTPStatusCode, "Status code of the last HTTP response.", Gauge)
+	r.Register(MonitorICMPRTT, "Average round trip time of the last ICMP measurement.", Gauge)
+	r.Register(MonitorICMPPacketLoss, "Share of packets lost by the last ICMP measurement.", Gauge)
+	r.Register(MonitorCertificateExpiry, "Days until the earliest expiring certificate of the last measurement expires.", Gauge)
+	r.Register(DistributorMonitors, "Number of monitors being polled.", Gauge)
+	r.Register(DistributorSubscribers, "Number of connected feed subscribers.", Gauge)
+	r.Register(DistributorQueueDepth, "Number of jobs waiting for a worker.", Gauge)
+	r.Register(DistributorQueueCapacity, "Maximum number of queued jobs.", Gauge)
+	r.Register(DistributorActive, "Number of jobs currently running.", Gauge)
+	r.Register(DistributorDropped, "Number of jobs dropped because the queue was full.", Counter)
+	r.Register(PluginExecutions, "Number of plugin executions by result.", Counter)
+	r.Register(RepositoryErrors, "Number of failed repository operations.", Counter)
+	r.Register(SinkRecords, "Number of measurements handled by each sink by result.", Counter)
+	return r
+}
+
+// States are the values of the state label of `MonitorState`.
+var States = []measurement.ProbeState{measurement.Ok, measurement.Warn, measurement.Dead, measurement.Unreachable}
+
+// RecordMeasurement will update the monitor metrics of the registry from a measurement.
+// Retry attempts only update the duration.
+func (r *Registry) RecordMeasurement(name string, m measurement.Measurement, now time.Time) {
+	if m.MonitorId == nil {
+		return
+	}
+	id := strconv.Itoa(*m.MonitorId)
+	labels := []string{"monitor_id", id, "monitor_name", name}
+
+	seconds := m.Duration / 1000
+	r.Set(MonitorLastDuration, seconds, labels...)
+	r.Observe(MonitorDuration, seconds, labels...)
+	if m.RetryAttempt != nil {
+		return
+	}
+
+	for _, v := range States {
+		value := 0.0
+		if v == m.State {
+			value = 1
+		}
+		r.Set(MonitorState, value, append(labels, "state", string(v))...)
+	}
+	r.Inc(MonitorMeasurements, append(labels, "state", string(m.State))...)
+
+	if m.HTTPStatusCode != nil {
+		r.Set(MonitorHTTPStatusCode, float64(*m.HTTPStatusCode), labels...)
+	}
+	if m.ICMPAvgRTT != nil {
+		r.Set(MonitorICMPRTT, *m.ICMPAvgRTT/1000, labels...)
+	}
+	if m.ICMPPacketsIn != nil && m.ICMPPacketsOut != nil && *m.ICMPPacketsOut > 0 {
+		lost := float64(*m.ICMPPacketsOut-*m.ICMPPacketsIn) / float64(*m.ICMPPacketsOut)
+		r.Set(MonitorICMPPacketLoss, max(lost, 0), labels...)
+	}
+	if len(m.Certificates) > 0 {
+		expiry := m.Certificates[0].NotAfter.Time()
+		for _, v := range m.Certificates[1:] {
+			if v.NotAfter.Time().Before(expiry) {
+				expiry = v.NotAfter.Time()
+			}
+		}
+		r.Set(MonitorCertificateExpiry, expiry.Sub(now).Hours()/24, labels...)
+	}
+}
@@ -0,0 +1,304 @@
+package monitor
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+type HTTPAssertionKind string
+
+const (
+	Contains    HTTPAssertionKind = "CONTAINS"
+	NotContains HTTPAssertionKind = "NOT_CONTAINS"
+	Regex       HTTPAssertionKind = "REGEX"
+	JSONPath    HTTPAssertionKind = "JSON_PATH"
+)
+
+type HTTPAssertionOperator string
+
+const (
+	Equal              HTTPAssertionOperator = "=="
+	NotEqual           HTTPAssertionOperator = "!="
+	LessThan           HTTPAssertionOperator = "<"
+	LessThanOrEqual    HTTPAssertionOperator = "<="
+	GreaterThan        HTTPAssertionOperator = ">"
+	GreaterThanOrEqual HTTPAssertionOperator = ">="
+)
+
+// observedLimit is the maximum length of an observed value included in a hint.
+const observedLimit = 64
+
+// HTTPAssertion is a check performed against the body of an HTTP response.
+type HTTPAssertion struct {
+	Kind HTTPAssertionKind `json:"kind"`
+	// Path is a JSONPath style expression, such as `$.data.items[0].status`.
+	//
+	// Only used by `JSONPath` assertions.
+	Path *string `json:"path"`
+	// Operator is used to compare the value found at `Path` with `Value`.
+	//
+	// Only used by `JSONPath` assertions, and defaults to `Equal`.
+	Operator *HTTPAssertionOperator `json:"operator"`
+	// Value is the substring, expression or comparison value, depending on `Kind`.
+	Value string `json:"value"`
+	// Mod is the state applied to the measurement when the assertion fails.
+	//
+	// Defaults to `DEAD`.
+	Mod *string `json:"mod"`
+}
+
+// Validate will return an error if the `HTTPAssertion` is in an invalid state.
+func (a HTTPAssertion) Validate() error {
+	switch a.Kind {
+	case Contains, NotContains:
+	case Regex:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("assertion expression `%v` is invalid", a.Value)
+		}
+	case JSONPath:
+		if a.Path == nil {
+			return errors.New("assertion path is required")
+		}
+		if _, err := parseJSONPath(*a.Path); err != nil {
+			return fmt.Errorf("assertion path `%v` is invalid", *a.Path)
+		}
+		if a.Operator != nil {
+			switch *a.Operator {
+			case Equal, NotEqual:
+			case LessThan, LessThanOrEqual, GreaterThan, GreaterThanOrEqual:
+				if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+					return fmt.Errorf("assertion value `%v` must be a number", a.Value)
+				}
+			default:
+				return fmt.Errorf("assertion operator `%v` is invalid", *a.Operator)
+			}
+		}
+	default:
+		return fmt.Errorf("assertion kind `%v` is invalid", a.Kind)
+	}
+	if a.Mod != nil && *a.Mod != string(measurement.Warn) && *a.Mod != string(measurement.Dead) {
+		return errors.New("assertion mod must be `WARN` or `DEAD`")
+	}
+
+	return nil
+}
+
+// Evaluate will check the assertion against the body, downgrading the span if it fails.
+func (a HTTPAssertion) Evaluate(span *measurement.Span, body []byte) {
+	mod := measurement.Dead
+	if a.Mod != nil {
+		mod = measurement.ProbeState(*a.Mod)
+	}
+
+	switch a.Kind {
+	case Contains:
+		if !bytes.Contains(body, []byte(a.Value)) {
+			span.Downgrade(mod, fmt.Sprintf("Assertion failed: body does not contain %q, observed %q.", a.Value, truncate(string(body))))
+		}
+	case NotContains:
+		if bytes.Contains(body, []byte(a.Value)) {
+			span.Downgrade(mod, fmt.Sprintf("Assertion failed: body contains %q.", a.Value))
+		}
+	case Regex:
+		expression, err := regexp.Compile(a.Value)
+		if err != nil {
+			span.Downgrade(mod, fmt.Sprintf("Assertion failed: expression %q is invalid.", a.Value))
+			return
+		}
+		if !expression.Match(body) {
+			span.Downgrade(mod, fmt.Sprintf("Assertion failed: body does not match %q, observed %q.", a.Value, truncate(string(body))))
+		}
+	case JSONPath:
+		operator := Equal
+		if a.Operator != nil {
+			operator = *a.Operator
+		}
+		actual, err := selectJSONPath(body, *a.Path)
+		if err != nil {
+			span.Downgrade(mod, fmt.Sprintf("Assertion failed: %v %v %q, %v.", *a.Path, operator, a.Value, err))
+			return
+		}
+		if !compareJSONValue(actual, operator, a.Value) {
+			span.Downgrade(mod, fmt.Sprintf("Assertion failed: %v %v %q, observed %q.", *a.Path, operator, a.Value, truncate(actual)))
+		}
+	}
+}
+
+// compareJSONValue compares a value selected from a JSON document with the expected value.
+//
+// Ordering operators compare numerically. Equality operators compare numerically when both
+// values are numbers, otherwise the strings are compared.
+func compareJSONValue(actual string, operator HTTPAssertionOperator, expected string) bool {
+	a, aerr := strconv.ParseFloat(actual, 64)
+	e, eerr := strconv.ParseFloat(expected, 64)
+	numeric := aerr == nil && eerr == nil
+
+	switch operator {
+	case Equal:
+		return numeric && a == e || !numeric && actual == expected
+	case NotEqual:
+		return numeric && a != e || !numeric && actual != expected
+	}
+	if !numeric {
+		return false
+	}
+	switch operator {
+	case LessThan:
+		return a < e
+	case LessThanOrEqual:
+		return a <= e
+	case GreaterThan:
+		return a > e
+	case GreaterThanOrEqual:
+		return a >= e
+	}
+
+	return false
+}
+
+// selectJSONPath returns the value found at the path within the JSON document as a string.
+//
+// Strings are returned without quotes, and objects or arrays are returned as compact JSON.
+func selectJSONPath(document []byte, path string) (string, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var current any
+	if err := decoder.Decode(&current); err != nil {
+		return "", errors.New("body is not valid JSON")
+	}
+
+	for _, v := range segments {
+		switch x := current.(type) {
+		case map[string]any:
+			value, ok := x[v]
+			if !ok {
+				return "", fmt.Errorf("key %q was not found", v)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(v)
+			if err != nil || index < 0 || index >= len(x) {
+				return "", fmt.Errorf("index %q is out of range", v)
+			}
+			current = x[index]
+		default:
+			return "", fmt.Errorf("segment %q was not found", v)
+		}
+	}
+
+	switch x := current.(type) {
+	case string:
+		return x, nil
+	case json.Number:
+		return x.String(), nil
+	case nil:
+		return "null", nil
+	default:
+		encoded, err := json.Marshal(x)
+		return string(encoded), err
+	}
+}
+
+// parseJSONPath splits a JSONPath style expression into keys and indices.
+//
+// Supports dot notation (`$.a.b`), indices (`$.a[0]`) and quoted keys (`$['a.b']`).
+// The leading `$` is optional.
+func parseJSONPath(path string) ([]string, error) {
+	segments := []string{}
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, errors.New("empty key")
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, errors.New("unterminated bracket")
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				inner = inner[1 : len(inner)-1]
+			} else if _, err := strconv.Atoi(inner); err != nil {
+				return nil, fmt.Errorf("invalid index %q", inner)
+			}
+			segments = append(segments, inner)
+			rest = rest[end+1:]
+		default:
+			if len(segments) > 0 {
+				return nil, fmt.Errorf("unexpected character %q", rest[0])
+			}
+			// Allow a bare leading key, like `a.b`.
+			rest = "." + rest
+		}
+	}
+
+	return segments, nil
+}
+
+// truncate shortens a string for inclusion in a hint.
+func truncate(value string) string {
+	runes := []rune(value)
+	if len(runes) > observedLimit {
+		return string(runes[:observedLimit]) + "..."
+	}
+	return value
+}
+
+type HTTPAssertionList []HTTPAssertion
+
+// Value implements `driver.Valuer` for `HTTPAssertionList`.
+func (h HTTPAssertionList) Value() (driver.Value, error) {
+	if len(h) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(h)
+}
+
+// Scan implements `sql.Scanner` for `HTTPAssertionList`.
+// This allows storing and fetching the `HTTPAssertionList` as a JSON array.
+func (h *HTTPAssertionList) Scan(value any) error {
+	if value == nil {
+		*h = []HTTPAssertion{}
+		return nil
+	}
+	var err error
+	switch x := value.(type) {
+	case string:
+		err = json.Unmarshal([]byte(x), h)
+	case []byte:
+		err = json.Unmarshal(x, h)
+	}
+	return err
+}
+
+// MarshalJSON implements `json.Marshaler` for `HTTPAssertionList`.
+func (h HTTPAssertionList) MarshalJSON() ([]byte, error) {
+	if h == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]HTTPAssertion(h))
+}
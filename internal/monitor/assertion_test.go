@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	for path, expect := range map[string][]string{
+		"$":                   {},
+		"$.a.b":               {"a", "b"},
+		"a.b[2].c":            {"a", "b", "2", "c"},
+		"$['a.b'][0]":         {"a.b", "0"},
+		`$.data["key"].value`: {"data", "key", "value"},
+	} {
+		got, err := parseJSONPath(path)
+		if err != nil {
+			t.Fatalf("failed to parse %v: %v", path, err)
+		}
+		debug.AssertDeepEqual(t, got, expect)
+	}
+
+	for _, path := range []string{"$..a", "$.a[", "$.a[x]"} {
+		_, err := parseJSONPath(path)
+		debug.Assert(t, err != nil, "expected error for path", path)
+	}
+}
+
+func TestHTTPAssertionEvaluate(t *testing.T) {
+	body := []byte(`{ "status": "ok", "queue": { "depth": 12 }, "items": [ { "id": 1 } ] }`)
+	warn := string(measurement.Warn)
+	path := func(p string) *string { return &p }
+	operator := func(o HTTPAssertionOperator) *HTTPAssertionOperator { return &o }
+
+	cases := []struct {
+		assertion HTTPAssertion
+		state     measurement.ProbeState
+	}{
+		{HTTPAssertion{Kind: Contains, Value: `"ok"`}, measurement.Ok},
+		{HTTPAssertion{Kind: Contains, Value: "error"}, measurement.Dead},
+		{HTTPAssertion{Kind: NotContains, Value: "error"}, measurement.Ok},
+		{HTTPAssertion{Kind: NotContains, Value: "status", Mod: &warn}, measurement.Warn},
+		{HTTPAssertion{Kind: Regex, Value: `"depth":\s*\d+`}, measurement.Ok},
+		{HTTPAssertion{Kind: Regex, Value: `^<html>`}, measurement.Dead},
+		{HTTPAssertion{Kind: JSONPath, Path: path("$.status"), Value: "ok"}, measurement.Ok},
+		{HTTPAssertion{Kind: JSONPath, Path: path("$.status"), Operator: operator(NotEqual), Value: "ok"}, measurement.Dead},
+		{HTTPAssertion{Kind: JSONPath, Path: path("$.queue.depth"), Operator: operator(LessThan), Value: "100"}, measurement.Ok},
+		{HTTPAssertion{Kind: JSONPath, Path: path("$.queue.depth"), Operator: operator(GreaterThan), Value: "100"}, measurement.Dead},
+		{HTTPAssertion{Kind: JSONPath, Path: path("$.items[0].id"), Value: "1.0"}, measurement.Ok},
+		{HTTPAssertion{Kind: JSONPath, Path: path("$.items[3].id"), Value: "1"}, measurement.Dead},
+	}
+
+	for _, v := range cases {
+		span := measurement.NewSpan()
+		v.assertion.Evaluate(&span, body)
+		debug.AssertEqual(t, span.State, v.state)
+	}
+}
+
+func TestHTTPAssertionHint(t *testing.T) {
+	path := "$.status"
+	span := measurement.NewSpan()
+	HTTPAssertion{Kind: JSONPath, Path: &path, Value: "ok"}.Evaluate(&span, []byte(`{ "status": "degraded" }`))
+	debug.AssertDeepEqual(t, []string(span.StateHint), []string{`Assertion failed: $.status == "ok", observed "degraded".`})
+}
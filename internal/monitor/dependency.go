@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// DependencyGraph maps the id of each monitor to the id of its parents.
+type DependencyGraph map[int][]int
+
+// NewDependencyGraph returns a `DependencyGraph` for the monitors.
+func NewDependencyGraph(monitors []Monitor) DependencyGraph {
+	graph := DependencyGraph{}
+	for _, v := range monitors {
+		if v.Id != nil {
+			graph[*v.Id] = v.ParentId
+		}
+	}
+	return graph
+}
+
+// DependsOn returns true if the monitor with id `from` depends on the monitor with
+// id `target`, directly or through any of its parents.
+func (g DependencyGraph) DependsOn(from int, target int) bool {
+	visited := map[int]bool{}
+	stack := []int{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		for _, v := range g[id] {
+			if v == target {
+				return true
+			}
+			stack = append(stack, v)
+		}
+	}
+	return false
+}
+
+// validateParents will return an error if a parent of the `Monitor` does not exist,
+// or if the parents would create a dependency cycle.
+func (s MonitorService) validateParents(ctx context.Context, monitor Monitor) error {
+	if len(monitor.ParentId) == 0 {
+		return nil
+	}
+
+	monitors, err := s.Repository.SelectMonitor(ctx, 0, nil)
+	if err != nil {
+		return err
+	}
+	graph := NewDependencyGraph(monitors)
+
+	errors := []string{}
+	for _, v := range monitor.ParentId {
+		if _, ok := graph[v]; !ok {
+			errors = append(errors, "value for field `parentId` contains a monitor that does not exist")
+			break
+		}
+	}
+	if monitor.Id != nil {
+		graph[*monitor.Id] = monitor.ParentId
+		if slices.ContainsFunc(monitor.ParentId, func(v int) bool { return graph.DependsOn(v, *monitor.Id) }) {
+			errors = append(errors, "value for field `parentId` must not create a dependency cycle")
+		}
+	}
+
+	if len(errors) > 0 {
+		return env.NewValidation(errors...)
+	}
+	return nil
+}
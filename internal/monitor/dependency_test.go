@@ -0,0 +1,27 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	one, two, three, four := 1, 2, 3, 4
+	graph := NewDependencyGraph([]Monitor{
+		{Id: &one},
+		{Id: &two, ParentId: []int{1}},
+		{Id: &three, ParentId: []int{2}},
+		{Id: &four, ParentId: []int{1, 3}},
+	})
+
+	debug.Assert(t, graph.DependsOn(3, 1), "expected transitive dependency")
+	debug.Assert(t, graph.DependsOn(4, 2), "expected transitive dependency through second parent")
+	debug.Assert(t, !graph.DependsOn(1, 4), "unexpected dependency")
+	debug.Assert(t, !graph.DependsOn(2, 3), "unexpected dependency")
+
+	// Making the root depend on a leaf creates a cycle.
+	graph[1] = []int{4}
+	debug.Assert(t, graph.DependsOn(4, 1), "expected cycle")
+	debug.Assert(t, graph.DependsOn(1, 1), "expected cycle")
+}
@@ -5,40 +5,96 @@ import (
 	"encoding/json"
 	"math"
 	"math/rand/v2"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/jmkng/zenin/internal/credential"
 	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/maintenance"
 	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/metrics"
 	"github.com/jmkng/zenin/internal/settings"
+	"github.com/jmkng/zenin/internal/sink"
 )
 
 // NewDistributor returns a new `Distributor`.
-func NewDistributor(m1 measurement.MeasurementService, m2 settings.Settings) Distributor {
+//
+// Probes and events are limited to the number of workers, or the defaults if zero.
+// Confirmed measurements are offered to the sinks once they are stored.
+func NewDistributor(m1 measurement.MeasurementService, c1 credential.CredentialService, m2 settings.Settings, probes, events int, sinks sink.Exporters) Distributor {
+	if probes <= 0 {
+		probes = DefaultProbeWorkers
+	}
+	if events <= 0 {
+		events = DefaultEventWorkers
+	}
+	ctx, cancel := context.WithCancel(context.Background())
 	return Distributor{
+		ctx:         ctx,
+		cancel:      cancel,
 		subscribers: map[int]*websocket.Conn{},
 		polling:     map[int]chan<- any{},
+		names:       map[int]string{},
+		states:      map[int]measurement.ProbeState{},
+		probes:      NewWorkerPool(probes),
+		events:      NewWorkerPool(events),
 		measurement: m1,
+		credential:  c1,
 		settings:    m2,
+		sinks:       sinks,
 	}
 }
 
 // Distributor handles polling actions, and distributes `Measurement` information.
 type Distributor struct {
+	// Cancelled to stop running probes and events.
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	// A list of active feed subscriber connections.
 	subscribers map[int]*websocket.Conn
 	// A list of polling monitors, and a channel to contact them.
 	polling map[int]chan<- any
+	// The name of each polling monitor, used to label metrics.
+	names map[int]string
 
 	measurement measurement.MeasurementService
+	credential  credential.CredentialService
 	settings    settings.Settings
+	// Receive confirmed measurements after they are stored.
+	sinks sink.Exporters
+
+	// Runs probes for all monitors.
+	probes *WorkerPool
+	// Runs events for all monitors, separate from probes so slow plugins can't starve polling.
+	events *WorkerPool
+
+	// A list of all maintenance windows, read by the monitor threads.
+	windows      []maintenance.Maintenance
+	windowsMutex sync.RWMutex
+	// The state of the last confirmed measurement for each polling monitor, used to resolve dependencies.
+	states      map[int]measurement.ProbeState
+	statesMutex sync.RWMutex
 }
 
 // Listen will block and listen for incoming messages.
 func (d *Distributor) Listen(s chan any) {
 	env.Debug("distributor starting")
 
+	// Closed once shutdown is complete, nil until shutdown starts.
+	var shutdown chan<- struct{}
+
 	for message := range s {
+		if shutdown != nil {
+			switch message.(type) {
+			case StartMessage, PollMessage, PushMessage:
+				env.Debug("distributor dropped message during shutdown", "message", message)
+				continue
+			}
+		}
+
 		switch x := message.(type) {
 		case SubscribeMessage:
 			d.subscribe(s, x.Subscriber)
@@ -54,10 +110,43 @@ func (d *Distributor) Listen(s chan any) {
 			if monitor, ok := d.polling[*x.Monitor.Id]; ok {
 				monitor <- x
 			} else {
-				go d.poll(s, x.Monitor)
+				m := x.Monitor
+				submitted := d.probes.TrySubmit(func() {
+					m.Previous = d.lastState(*m.Id)
+					m.Maintenance = d.findMaintenance(*m.Id, time.Now())
+					m.ParentDown = func() bool { return d.parentDown(m.ParentId) }
+					d.poll(s, m, nil)
+				})
+				if !submitted {
+					env.Warn("distributor dropped poll, probe queue is full", "monitor(id)", *m.Id)
+				}
+			}
+		case PushMessage:
+			if monitor, ok := d.polling[x.Id]; ok {
+				monitor <- x
+			} else {
+				env.Debug("distributor dropped push for inactive monitor", "monitor(id)", x.Id)
 			}
 		case settings.SettingsMessage:
 			d.settings = x.Settings
+		case StatsMessage:
+			x.Reply <- DistributorStats{Monitors: len(d.polling), Subscribers: len(d.subscribers),
+				Probes: d.probes.Stats(), Events: d.events.Stats()}
+		case ShutdownMessage:
+			if shutdown != nil {
+				continue
+			}
+			shutdown = x.Done
+			d.drain(s)
+		case drainedMessage:
+			d.closeSubscribers()
+			close(shutdown)
+			env.Debug("distributor stopping")
+			return
+		case maintenance.MaintenanceMessage:
+			d.windowsMutex.Lock()
+			d.windows = x.Windows
+			d.windowsMutex.Unlock()
 		default:
 			env.Debug("distributor dropped unrecognized message: %v", "message", message)
 		}
@@ -121,12 +210,59 @@ func (d *Distributor) start(loopback chan<- any, mon Monitor) {
 	}
 	channel := make(chan any)
 	d.polling[*mon.Id] = channel
+	d.names[*mon.Id] = mon.Name
 
 	go func(loopback chan<- any, in <-chan any, mon Monitor) {
 		delay := rand.IntN(800)
 		env.Debug("distributor started polling monitor", "monitor(id)", *mon.Id, "delay(ms)", delay)
 		time.Sleep(time.Duration(delay) * time.Millisecond)
 
+		// Each measurement is sent back here, so failures can be retried.
+		results := make(chan measurement.Measurement, 8)
+		// The number of unconfirmed failures.
+		failures := 0
+		// The state of the last confirmed measurement.
+		previous := d.lastState(*mon.Id)
+		if previous != nil {
+			// Dependent monitors read the state before the first measurement is distributed.
+			d.statesMutex.Lock()
+			if _, ok := d.states[*mon.Id]; !ok {
+				d.states[*mon.Id] = *previous
+			}
+			d.statesMutex.Unlock()
+		}
+		mon.Flap = NewFlapDetector()
+		next := time.Now().Add(mon.Wait(time.Now()))
+
+		// prepare returns a copy of the monitor with the state needed to poll it.
+		prepare := func() Monitor {
+			attempt := mon
+			attempt.Attempt = failures
+			attempt.Previous = previous
+			attempt.Maintenance = d.findMaintenance(*mon.Id, time.Now())
+			attempt.ParentDown = func() bool { return d.parentDown(mon.ParentId) }
+			return attempt
+		}
+
+		// busy is true while a poll is queued or running, so polls of the monitor never overlap.
+		busy := false
+		// A push received while busy, delivered once the poll is finished.
+		var pending *Push
+
+		// submit will queue a poll on the probe pool, and return false if it was skipped.
+		submit := func(m Monitor) bool {
+			if busy {
+				env.Debug("distributor skipped poll, monitor is already polling", "monitor(id)", *mon.Id)
+				return false
+			}
+			if !d.probes.TrySubmit(func() { d.poll(loopback, m, results) }) {
+				env.Warn("distributor dropped poll, probe queue is full", "monitor(id)", *mon.Id)
+				return false
+			}
+			busy = true
+			return true
+		}
+
 	POLLING:
 		for {
 			select {
@@ -136,14 +272,56 @@ func (d *Distributor) start(loopback chan<- any, mon Monitor) {
 					break POLLING
 				}
 
-				switch message.(type) {
+				switch x := message.(type) {
 				case StopMessage:
 					break POLLING
 				case PollMessage:
-					go d.poll(loopback, mon)
+					if mon.Kind == measurement.Push {
+						env.Debug("distributor dropped poll request for push monitor", "monitor(id)", *mon.Id)
+						continue
+					}
+					submit(prepare())
+				case PushMessage:
+					// Receiving the push restarts the wait.
+					next = time.Now().Add(mon.Wait(time.Now()))
+					if busy {
+						pending = &x.Push
+						continue
+					}
+					pushed := prepare()
+					pushed.Push = &x.Push
+					submit(pushed)
+				}
+			case result := <-results:
+				busy = false
+				// Unreachable failures belong to the parent, so they are not confirmed or retried here.
+				// Measurements taken during maintenance are ignored too, so a state that began
+				// during the window is treated as a change on the first poll after it.
+				if result.State != measurement.Unreachable && !result.Maintenance {
+					if result.RetryAttempt == nil {
+						// Confirmed, so the next change of state is retried from the start.
+						previous = &result.State
+						failures = 0
+					} else {
+						failures++
+						next = time.Now().Add(mon.RetryWait())
+						env.Debug("distributor scheduled retry", "monitor(id)", *mon.Id, "attempt", failures)
+					}
+				}
+				if pending != nil {
+					pushed := prepare()
+					pushed.Push = pending
+					pending = nil
+					submit(pushed)
+				}
+			case <-time.After(time.Until(next)):
+				next = time.Now().Add(mon.Wait(time.Now()))
+				attempt := prepare()
+				if attempt.Maintenance != nil && attempt.Maintenance.Mode == maintenance.Pause {
+					env.Debug("distributor paused polling during maintenance", "monitor(id)", *mon.Id, "maintenance(id)", *attempt.Maintenance.Id)
+					continue
 				}
-			case <-time.After(time.Duration(mon.Interval) * time.Second):
-				go d.poll(loopback, mon)
+				submit(attempt)
 			}
 		}
 
@@ -151,14 +329,124 @@ func (d *Distributor) start(loopback chan<- any, mon Monitor) {
 	}(loopback, channel, mon)
 }
 
+// Shutdown will stop polling all monitors, and block until running probes and events are
+// finished and their measurements are distributed. Feed subscribers are then disconnected.
+//
+// If the context is done first, running probes and events are cancelled and the
+// context error is returned once they have stopped.
+func (d *Distributor) Shutdown(ctx context.Context, s chan<- any) error {
+	done := make(chan struct{})
+	sent := false
+	select {
+	case s <- ShutdownMessage{Done: done}:
+		sent = true
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+		}
+	case <-ctx.Done():
+	}
+
+	// Cancelled probes and events finish quickly, and the caller may close
+	// the repository once this returns, so wait for them anyway.
+	d.cancel()
+	if !sent {
+		s <- ShutdownMessage{Done: done}
+	}
+	<-done
+	return ctx.Err()
+}
+
+// drain will stop all polling loops, and send a `drainedMessage` to the loopback
+// once queued probes and events are finished.
+func (d *Distributor) drain(loopback chan<- any) {
+	probes, events := d.probes.Stats(), d.events.Stats()
+	env.Info("distributor draining", "monitors", len(d.polling), "probes(active)", probes.Active, "probes(queued)", probes.Queued,
+		"events(active)", events.Active, "events(queued)", events.Queued)
+	for id := range d.polling {
+		d.stop(id)
+	}
+
+	go func() {
+		// Probes queue events, so they must finish first.
+		d.probes.Close()
+		d.events.Close()
+		loopback <- drainedMessage{}
+	}()
+}
+
+// closeSubscribers will send a close frame to each feed subscriber, and close the connection.
+func (d *Distributor) closeSubscribers() {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server is shutting down")
+	for id, conn := range d.subscribers {
+		err := conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+		if err != nil {
+			env.Debug("distributor failed to send close frame to feed subscriber", "subscriber(id)", id, "error", err)
+		}
+		conn.Close()
+		delete(d.subscribers, id)
+	}
+}
+
 // poll will begin polling a `Monitor`.
-func (d *Distributor) poll(loopback chan<- any, m Monitor) {
-	measurement := m.Poll(d.settings)
+//
+// The measurement is sent to `results` if it is not nil and has capacity.
+func (d *Distributor) poll(loopback chan<- any, m Monitor, results chan<- measurement.Measurement) {
+	if m.CredentialId != nil {
+		material, err := d.credential.GetMaterial(context.Background(), *m.CredentialId)
+		if err != nil {
+			env.Error("distributor failed to load monitor credential", "monitor(id)", *m.Id, "credential(id)", *m.CredentialId, "error", err)
+		} else {
+			m.Credential = &material
+		}
+	}
+
+	m.EventPool = d.events
+	measurement := m.Poll(d.ctx, d.settings)
+	if results != nil {
+		select {
+		case results <- measurement:
+		default:
+		}
+	}
 	loopback <- MeasurementMessage{
 		Measurement: measurement,
 	}
 }
 
+// findMaintenance returns the maintenance window that is active for the monitor at the time, or nil.
+func (d *Distributor) findMaintenance(id int, t time.Time) *maintenance.Maintenance {
+	d.windowsMutex.RLock()
+	defer d.windowsMutex.RUnlock()
+	return maintenance.Find(d.windows, id, t)
+}
+
+// lastState returns the state of the last confirmed measurement recorded for the monitor,
+// or nil if there is none.
+func (d *Distributor) lastState(id int) *measurement.ProbeState {
+	state, err := d.measurement.Repository.SelectLastState(context.Background(), id)
+	if err != nil {
+		env.Error("distributor failed to load last monitor state", "monitor(id)", id, "error", err)
+		return nil
+	}
+	return state
+}
+
+// parentDown returns true if the last confirmed state of any parent monitor is `Dead` or `Unreachable`.
+//
+// Only polling parents are considered, so a parent that is deactivated or deleted never holds its children down.
+func (d *Distributor) parentDown(parents []int) bool {
+	d.statesMutex.RLock()
+	defer d.statesMutex.RUnlock()
+	for _, v := range parents {
+		if state, ok := d.states[v]; ok && (state == measurement.Dead || state == measurement.Unreachable) {
+			return true
+		}
+	}
+	return false
+}
+
 // stop will stop polling an active `Monitor`.
 func (d *Distributor) stop(id int) {
 	channel, exists := d.polling[id]
@@ -171,18 +459,44 @@ func (d *Distributor) stop(id int) {
 	// but we include the id anyway.
 	channel <- StopMessage{Id: id}
 	delete(d.polling, id)
+	delete(d.names, id)
+	d.statesMutex.Lock()
+	delete(d.states, id)
+	d.statesMutex.Unlock()
+	metrics.Default.Delete("monitor_id", strconv.Itoa(id))
 }
 
 // distributeMeasurement will distribute a `Measurement` to the repository and feed subscribers.
 func (d *Distributor) distributeMeasurement(loopback chan<- any, m measurement.Measurement) {
+	if m.RetryAttempt == nil && m.MonitorId != nil {
+		if _, ok := d.polling[*m.MonitorId]; ok {
+			d.statesMutex.Lock()
+			d.states[*m.MonitorId] = m.State
+			d.statesMutex.Unlock()
+		}
+	}
+
+	if m.MonitorId != nil {
+		// Monitors that are not polling are not exported, so stopped monitors don't reappear.
+		if name, ok := d.names[*m.MonitorId]; ok {
+			metrics.Default.RecordMeasurement(name, m, time.Now())
+		}
+	}
+
 	id, err := d.measurement.Repository.InsertMeasurement(context.Background(), m)
 	if err != nil {
+		metrics.Default.Inc(metrics.RepositoryErrors, "operation", "insert_measurement")
 		env.Error("distributor failed to send measurement to repository (aborted distribution)", "error", err)
 		return
 	}
 	m.Id = &id
 	env.Info("distributing measurement", "measurement(id)", id, "subscribers(count)", len(d.subscribers))
 
+	if m.RetryAttempt == nil && m.MonitorId != nil {
+		// Exporters never block, a full buffer drops the record.
+		d.sinks.Offer(sink.Record{MonitorName: d.names[*m.MonitorId], Measurement: m})
+	}
+
 	message, err := json.Marshal(m)
 	if err != nil {
 		env.Error("distributor failed to serialize measurement (aborted distribution)", "measurement", m, "error", err)
@@ -0,0 +1,236 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/maintenance"
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/settings"
+)
+
+// testMeasurementRepository records inserted measurements, and returns the stored last states.
+type testMeasurementRepository struct {
+	mutex    sync.Mutex
+	inserted []measurement.Measurement
+	last     map[int]measurement.ProbeState
+}
+
+func (t *testMeasurementRepository) InsertMeasurement(ctx context.Context, m measurement.Measurement) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.inserted = append(t.inserted, m)
+	return len(t.inserted), nil
+}
+
+func (t *testMeasurementRepository) SelectCertificate(ctx context.Context, id int) ([]measurement.Certificate, error) {
+	return nil, nil
+}
+
+func (t *testMeasurementRepository) DeleteMeasurement(ctx context.Context, id []int) error {
+	return nil
+}
+
+func (t *testMeasurementRepository) SelectLastState(ctx context.Context, id int) (*measurement.ProbeState, error) {
+	if state, ok := t.last[id]; ok {
+		return &state, nil
+	}
+	return nil, nil
+}
+
+func (t *testMeasurementRepository) measurements() []measurement.Measurement {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]measurement.Measurement{}, t.inserted...)
+}
+
+func newTestDistributor(r *testMeasurementRepository) *Distributor {
+	d := NewDistributor(measurement.NewMeasurementService(r), credential.CredentialService{}, settings.Settings{}, 0, 0, nil)
+	return &d
+}
+
+// waitFor will call the function until it returns true, or fail the test after a few seconds.
+func waitFor(t *testing.T, message string, f func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDistributorParentDown(t *testing.T) {
+	r := &testMeasurementRepository{last: map[int]measurement.ProbeState{
+		1: measurement.Dead,
+		2: measurement.Dead,
+	}}
+	d := newTestDistributor(r)
+	loopback := make(chan any, 8)
+
+	// Parent 2 is dead but was never started, so it is ignored.
+	debug.Assert(t, !d.parentDown([]int{2}), "expected parent that is not polling to be ignored")
+
+	id := 1
+	parent := newTCPMonitor(t, "127.0.0.1:1")
+	parent.Id = &id
+	parent.Interval = 3600
+	d.start(loopback, parent)
+	waitFor(t, "expected polling parent with dead state to be down", func() bool { return d.parentDown([]int{1, 2}) })
+
+	// Stopping the parent, as when it is deactivated or deleted, releases its children.
+	d.stop(id)
+	debug.Assert(t, !d.parentDown([]int{1, 2}), "expected stopped parent to be ignored")
+
+	// Measurements that arrive after the parent stopped are not remembered.
+	m := measurement.Measurement{MonitorId: &id, Span: measurement.NewSpan()}
+	m.State = measurement.Dead
+	d.distributeMeasurement(loopback, m)
+	debug.Assert(t, !d.parentDown([]int{1}), "expected measurement of stopped parent to be ignored")
+}
+
+// newTestEvent returns a `Dead` event with a `StateChange` trigger, and a function that
+// returns the number of times it ran. The plugin appends a line to a file each time it runs.
+func newTestEvent(t *testing.T) (Event, func() int) {
+	if runtime.GOOS == "windows" {
+		t.Skip("event plugin is a shell script")
+	}
+
+	plugins, output := t.TempDir(), filepath.Join(t.TempDir(), "events")
+	script := "#!/bin/sh\necho ran >> " + output + "\n"
+	if err := os.WriteFile(filepath.Join(plugins, "event.sh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	dir := env.Env.PluginsDir
+	env.Env.PluginsDir = plugins
+	t.Cleanup(func() { env.Env.PluginsDir = dir })
+
+	id := 1
+	plugin := "event.sh"
+	threshold, trigger := Dead, StateChange
+	event := Event{Id: &id, Threshold: &threshold, Trigger: &trigger, PluginFields: PluginFields{PluginName: &plugin}}
+	return event, func() int {
+		b, _ := os.ReadFile(output)
+		return strings.Count(string(b), "\n")
+	}
+}
+
+// newClosedAddress returns an address that nothing is listening on.
+func newClosedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestDistributorRetry(t *testing.T) {
+	event, events := newTestEvent(t)
+	// Nothing is listening on the port until the monitor should recover.
+	address := newClosedAddress(t)
+
+	id, retries, wait := 1, 2, 1
+	m := newTCPMonitor(t, address)
+	m.Id = &id
+	m.Interval = 3600
+	m.Timeout = 1
+	m.RetryCount = &retries
+	m.RetryInterval = &wait
+	m.Events = []Event{event}
+
+	r := &testMeasurementRepository{}
+	d := newTestDistributor(r)
+	channel := make(chan any, 8)
+	go d.Listen(channel)
+	defer d.Shutdown(context.Background(), channel)
+	channel <- StartMessage{Monitor: m}
+
+	// poll will trigger a poll, and return the measurement once it is stored.
+	poll := func(count int) measurement.Measurement {
+		channel <- PollMessage{Monitor: m}
+		waitFor(t, "expected measurement to be stored", func() bool { return len(r.measurements()) >= count })
+		return r.measurements()[count-1]
+	}
+
+	// The failure is retried every `RetryInterval`, and confirmed on the last attempt.
+	first := poll(1)
+	debug.AssertEqual(t, first.State, measurement.Dead)
+	debug.AssertEqual(t, *first.RetryAttempt, 1)
+	waitFor(t, "expected failure to be retried", func() bool { return len(r.measurements()) >= 3 })
+	stored := r.measurements()
+	debug.AssertEqual(t, *stored[1].RetryAttempt, 2)
+	debug.Assert(t, stored[2].RetryAttempt == nil, "expected failure to be confirmed on the last attempt")
+	debug.Assert(t, stored[2].CreatedAt.Time().Sub(stored[0].CreatedAt.Time()) >= 2*time.Second,
+		"expected retries to wait for the retry interval")
+
+	// The event runs once, on confirmation, because retries did not change the previous state.
+	waitFor(t, "expected event to run on confirmed failure", func() bool { return events() == 1 })
+
+	// A confirmed failure is not retried again.
+	debug.Assert(t, poll(4).RetryAttempt == nil, "expected confirmed failure not to be retried")
+
+	// Recovering resets the failures, so the next failure is retried from the first attempt.
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, poll(5).State, measurement.Ok)
+	listener.Close()
+	failed := poll(6)
+	debug.AssertEqual(t, failed.State, measurement.Dead)
+	debug.AssertEqual(t, *failed.RetryAttempt, 1)
+	debug.AssertEqual(t, events(), 1)
+}
+
+func TestDistributorMaintenanceChange(t *testing.T) {
+	event, events := newTestEvent(t)
+
+	id, window := 1, 1
+	m := newTCPMonitor(t, newClosedAddress(t))
+	m.Id = &id
+	m.Interval = 3600
+	m.Timeout = 1
+	m.Events = []Event{event}
+
+	r := &testMeasurementRepository{}
+	d := newTestDistributor(r)
+	channel := make(chan any, 8)
+	go d.Listen(channel)
+	defer d.Shutdown(context.Background(), channel)
+	channel <- StartMessage{Monitor: m}
+
+	poll := func(count int) measurement.Measurement {
+		channel <- PollMessage{Monitor: m}
+		waitFor(t, "expected measurement to be stored", func() bool { return len(r.measurements()) >= count })
+		return r.measurements()[count-1]
+	}
+
+	// The monitor goes down while events are suppressed.
+	channel <- maintenance.MaintenanceMessage{Windows: []maintenance.Maintenance{{
+		Id:        &window,
+		Mode:      maintenance.Suppress,
+		StartsAt:  internal.NewTimeValue(time.Now().Add(-time.Hour)),
+		MonitorId: []int{id},
+	}}}
+	suppressed := poll(1)
+	debug.AssertEqual(t, suppressed.State, measurement.Dead)
+	debug.Assert(t, suppressed.Maintenance, "expected measurement during maintenance")
+
+	// Still down after the window, which is a change from the state before it.
+	channel <- maintenance.MaintenanceMessage{}
+	debug.AssertEqual(t, poll(2).State, measurement.Dead)
+	waitFor(t, "expected event to run on the first poll after maintenance", func() bool { return events() == 1 })
+}
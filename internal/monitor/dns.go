@@ -0,0 +1,281 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/measurement"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsRecordTypes maps the supported `DNSRecordType` values to their wire types.
+var dnsRecordTypes = map[DNSRecordType]dnsmessage.Type{
+	RecordA:     dnsmessage.TypeA,
+	RecordAAAA:  dnsmessage.TypeAAAA,
+	RecordCNAME: dnsmessage.TypeCNAME,
+	RecordMX:    dnsmessage.TypeMX,
+	RecordTXT:   dnsmessage.TypeTXT,
+	RecordNS:    dnsmessage.TypeNS,
+	RecordSRV:   dnsmessage.TypeSRV,
+	RecordSOA:   dnsmessage.TypeSOA,
+}
+
+// NewDNSProbe returns a new `DNSProbe`
+func NewDNSProbe() DNSProbe {
+	return DNSProbe{}
+}
+
+type DNSProbe struct{}
+
+// Poll implements `Probe.Poll` for `DNSProbe`.
+func (d DNSProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
+	span := measurement.NewSpan()
+
+	// Check remote address.
+	name, err := dnsmessage.NewName(strings.TrimSuffix(*m.RemoteAddress, ".") + ".")
+	if err != nil {
+		span.Downgrade(measurement.Dead, RemoteAddressInvalidMessage)
+		return span
+	}
+
+	resolver, err := resolverAddress(m.DNSResolver)
+	if err != nil {
+		span.Downgrade(measurement.Dead, "Resolver address may be invalid.")
+		if m.DNSResolver == nil {
+			span.Hint("System resolver could not be determined, provide a resolver address.")
+		}
+		return span
+	}
+
+	start := time.Now()
+	response, err := exchangeDNS(ctx, resolver, dnsmessage.Question{
+		Name:  name,
+		Type:  dnsRecordTypes[*m.DNSRecordType],
+		Class: dnsmessage.ClassINET,
+	})
+	rtt := float64(time.Since(start)) / float64(time.Millisecond)
+	if err != nil {
+		span.Downgrade(measurement.Dead)
+
+		var ne net.Error
+		if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &ne) && ne.Timeout() {
+			span.Hint(TimeoutMessage)
+		} else {
+			span.Hint("Unable to query resolver.")
+		}
+		return span
+	}
+
+	answers := formatDNSAnswers(response, dnsRecordTypes[*m.DNSRecordType])
+	span.DNSRTT = &rtt
+	span.DNSAnswers = answers
+
+	if response.RCode != dnsmessage.RCodeSuccess {
+		span.Downgrade(measurement.Dead, fmt.Sprintf("Resolver responded with %v.", rcodeName(response.RCode)))
+		return span
+	}
+	if len(answers) == 0 {
+		span.Downgrade(measurement.Dead, "Received no answers.")
+		return span
+	}
+	if len(m.DNSExpectedAnswers) > 0 && !matchDNSAnswers(m.DNSExpectedAnswers, answers) {
+		span.Downgrade(measurement.Dead, "Answers did not match expected values.")
+	}
+
+	return span
+}
+
+// exchangeDNS will send a query to the resolver and return the response.
+//
+// The query is sent over UDP first, and retried over TCP if the response is truncated.
+func exchangeDNS(ctx context.Context, address string, question dnsmessage.Question) (dnsmessage.Message, error) {
+	id := uint16(rand.IntN(math.MaxUint16 + 1))
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{question},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return dnsmessage.Message{}, err
+	}
+
+	response, err := exchangeDNSOver(ctx, "udp", address, id, packed)
+	if err == nil && response.Truncated {
+		response, err = exchangeDNSOver(ctx, "tcp", address, id, packed)
+	}
+	return response, err
+}
+
+// exchangeDNSOver will send a packed query over the named network, and return
+// the first response with a matching id.
+func exchangeDNSOver(ctx context.Context, network, address string, id uint16, packed []byte) (dnsmessage.Message, error) {
+	var response dnsmessage.Message
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return response, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if network == "tcp" {
+		prefix := binary.BigEndian.AppendUint16(make([]byte, 0, len(packed)+2), uint16(len(packed)))
+		packed = append(prefix, packed...)
+	}
+	if _, err := conn.Write(packed); err != nil {
+		return response, err
+	}
+
+	for {
+		var buffer []byte
+		if network == "tcp" {
+			length := make([]byte, 2)
+			if _, err := io.ReadFull(conn, length); err != nil {
+				return response, err
+			}
+			buffer = make([]byte, binary.BigEndian.Uint16(length))
+			if _, err := io.ReadFull(conn, buffer); err != nil {
+				return response, err
+			}
+		} else {
+			buffer = make([]byte, 1232)
+			n, err := conn.Read(buffer)
+			if err != nil {
+				return response, err
+			}
+			buffer = buffer[:n]
+		}
+
+		if err := response.Unpack(buffer); err != nil {
+			return response, err
+		}
+		if response.ID == id && response.Response {
+			return response, nil
+		}
+	}
+}
+
+// formatDNSAnswers returns the answers of the provided kind as strings.
+// Other records in the answer section, such as a CNAME chain leading to an A record, are ignored.
+func formatDNSAnswers(response dnsmessage.Message, kind dnsmessage.Type) internal.ArrayValue {
+	answers := internal.ArrayValue{}
+	name := func(n dnsmessage.Name) string {
+		return strings.TrimSuffix(n.String(), ".")
+	}
+
+	for _, v := range response.Answers {
+		if v.Header.Type != kind {
+			continue
+		}
+		switch b := v.Body.(type) {
+		case *dnsmessage.AResource:
+			answers = append(answers, net.IP(b.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			answers = append(answers, net.IP(b.AAAA[:]).String())
+		case *dnsmessage.CNAMEResource:
+			answers = append(answers, name(b.CNAME))
+		case *dnsmessage.MXResource:
+			answers = append(answers, fmt.Sprintf("%d %v", b.Pref, name(b.MX)))
+		case *dnsmessage.TXTResource:
+			answers = append(answers, strings.Join(b.TXT, ""))
+		case *dnsmessage.NSResource:
+			answers = append(answers, name(b.NS))
+		case *dnsmessage.SRVResource:
+			answers = append(answers, fmt.Sprintf("%d %d %d %v", b.Priority, b.Weight, b.Port, name(b.Target)))
+		case *dnsmessage.SOAResource:
+			answers = append(answers, fmt.Sprintf("%v %v %d %d %d %d %d",
+				name(b.NS), name(b.MBox), b.Serial, b.Refresh, b.Retry, b.Expire, b.MinTTL))
+		}
+	}
+
+	return answers
+}
+
+// matchDNSAnswers returns true if the expected and actual answers contain the same values.
+//
+// Order is not significant, comparison is case insensitive and trailing dots are ignored.
+func matchDNSAnswers(expected, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	normalize := func(values []string) []string {
+		result := []string{}
+		for _, v := range values {
+			result = append(result, strings.ToLower(strings.TrimSuffix(strings.TrimSpace(v), ".")))
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	return slices.Equal(normalize(expected), normalize(actual))
+}
+
+// resolverAddress returns a "host:port" resolver address.
+//
+// The port defaults to 53 if not specified. If the resolver is nil, the system resolver is used.
+func resolverAddress(resolver *string) (string, error) {
+	if resolver == nil || strings.TrimSpace(*resolver) == "" {
+		return systemResolver()
+	}
+	value := strings.TrimSpace(*resolver)
+	if _, _, err := net.SplitHostPort(value); err == nil {
+		return value, nil
+	}
+
+	return net.JoinHostPort(strings.Trim(value, "[]"), "53"), nil
+}
+
+// systemResolver returns the address of the first nameserver found in "/etc/resolv.conf".
+func systemResolver() (string, error) {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return net.JoinHostPort(fields[1], "53"), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", errors.New("no nameserver found")
+}
+
+// rcodeName returns the conventional name of a response code.
+func rcodeName(code dnsmessage.RCode) string {
+	switch code {
+	case dnsmessage.RCodeFormatError:
+		return "FORMERR"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeNotImplemented:
+		return "NOTIMP"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	default:
+		return fmt.Sprintf("RCODE %d", code)
+	}
+}
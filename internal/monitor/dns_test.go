@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestMatchDNSAnswers(t *testing.T) {
+	debug.Assert(t, matchDNSAnswers([]string{"b.example.com.", "A.example.com"}, []string{"a.example.com", "b.example.com"}))
+	debug.Assert(t, !matchDNSAnswers([]string{"a.example.com"}, []string{"a.example.com", "b.example.com"}))
+	debug.Assert(t, !matchDNSAnswers([]string{"10.0.0.1"}, []string{"10.0.0.2"}))
+}
+
+func TestResolverAddress(t *testing.T) {
+	for value, expect := range map[string]string{
+		"1.1.1.1":        "1.1.1.1:53",
+		"1.1.1.1:5353":   "1.1.1.1:5353",
+		"::1":            "[::1]:53",
+		"[::1]:5353":     "[::1]:5353",
+		"ns.example.com": "ns.example.com:53",
+	} {
+		got, err := resolverAddress(&value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		debug.AssertEqual(t, got, expect)
+	}
+}
+
+func TestDNSProbe(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Answer every query with two A records.
+	go func() {
+		buffer := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buffer[:n]); err != nil {
+				return
+			}
+			header := dnsmessage.ResourceHeader{Name: query.Questions[0].Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true},
+				Questions: query.Questions,
+				Answers: []dnsmessage.Resource{
+					{Header: header, Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+					{Header: header, Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}}},
+				},
+			}
+			packed, _ := response.Pack()
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	address := "example.com"
+	kind := RecordA
+	resolver := conn.LocalAddr().String()
+	m := Monitor{
+		Timeout:       2,
+		RemoteAddress: &address,
+		DNSFields: DNSFields{
+			DNSRecordType:      &kind,
+			DNSResolver:        &resolver,
+			DNSExpectedAnswers: internal.ArrayValue{"10.0.0.2", "10.0.0.1"},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	span := NewDNSProbe().Poll(ctx, m)
+	debug.AssertEqual(t, span.State, measurement.Ok)
+	debug.AssertDeepEqual(t, []string(span.DNSAnswers), []string{"10.0.0.1", "10.0.0.2"})
+
+	m.DNSExpectedAnswers = internal.ArrayValue{"10.0.0.3"}
+	span = NewDNSProbe().Poll(ctx, m)
+	debug.AssertEqual(t, span.State, measurement.Dead)
+}
@@ -20,6 +20,10 @@ func NewEventMonitor(m Monitor) EventMonitor {
 		PluginFields:  m.PluginFields,
 		HTTPFields:    m.HTTPFields,
 		ICMPFields:    m.ICMPFields,
+		DNSFields:     m.DNSFields,
+		TCPFields:     m.TCPFields,
+		UDPFields:     m.UDPFields,
+		MetricFields:  m.MetricFields,
 	}
 }
 
@@ -33,11 +37,15 @@ type EventMonitor struct {
 	Timeout       int
 	Description   *string
 	RemoteAddress *string
-	RemotePort    *int16
+	RemotePort    *int
 
 	PluginFields
 	HTTPFields
 	ICMPFields
+	DNSFields
+	TCPFields
+	UDPFields
+	MetricFields
 }
 
 // NewEventMeasurement returns a new `EventMeasurement`.
@@ -56,6 +64,8 @@ func NewEventMeasurement(m measurement.Measurement) EventMeasurement {
 		Certificates: certificates,
 		HTTPFields:   m.HTTPFields,
 		ICMPFields:   m.ICMPFields,
+		DNSFields:    m.DNSFields,
+		MetricFields: m.MetricFields,
 		PluginFields: m.PluginFields,
 	}
 }
@@ -71,6 +81,8 @@ type EventMeasurement struct {
 
 	measurement.HTTPFields
 	measurement.ICMPFields
+	measurement.DNSFields
+	measurement.MetricFields
 	measurement.PluginFields
 }
 
@@ -105,6 +117,20 @@ type EventThreshold string
 const (
 	Warn EventThreshold = "WARN"
 	Dead EventThreshold = "DEAD"
+	// Recovered runs when the state returns to `OK` from any other state.
+	Recovered EventThreshold = "RECOVERED"
+	// Flapping runs once when the monitor starts flapping, and once when it stops.
+	Flapping EventThreshold = "FLAPPING"
+)
+
+// EventTrigger determines how often an eligible `Event` runs.
+type EventTrigger string
+
+const (
+	// EveryPoll runs the event for every eligible measurement.
+	EveryPoll EventTrigger = "POLL"
+	// StateChange runs the event only when the state of the monitor changes.
+	StateChange EventTrigger = "CHANGE"
 )
 
 // Event is a plugin that can be executed based on a measurement state.
@@ -112,21 +138,42 @@ type Event struct {
 	Id        *int            `json:"-" db:"event_id"`
 	MonitorId *int            `json:"-" db:"event_monitor_id"`
 	Threshold *EventThreshold `json:"threshold" db:"threshold"`
+	// Trigger determines how often the event runs. Defaults to `EveryPoll`.
+	Trigger *EventTrigger `json:"trigger" db:"trigger_mode"`
 
 	PluginFields
 }
 
-// IsEligible will return true if the `Event` should run based on the provided `ProbeState`.
+// IsEligible will return true if the `Event` should run based on the provided `ProbeState`,
+// and the state of the previous measurement.
 //
 // A null threshold will always run. A warn threshold runs for warn and dead states,
-// and a dead threshold runs only for dead states.
-func (e Event) IsEligible(s measurement.ProbeState) bool {
+// a dead threshold runs only for dead states, and a recovered threshold runs only when
+// the state changes to ok. With a `StateChange` trigger, the event only runs if the state changed.
+//
+// A flapping threshold is never eligible based on state, see `FlapDetector`.
+//
+// A nil `previous` state means the monitor has no measurements, and is treated as ok.
+func (e Event) IsEligible(previous *measurement.ProbeState, s measurement.ProbeState) bool {
+	last := measurement.Ok
+	if previous != nil {
+		last = *previous
+	}
+	changed := last != s
+	if e.Trigger != nil && *e.Trigger == StateChange && !changed {
+		return false
+	}
 	if e.Threshold == nil {
 		return true
 	}
 
-	if *e.Threshold == Warn && s != measurement.Ok || *e.Threshold == Dead && s == measurement.Dead {
-		return true
+	switch *e.Threshold {
+	case Warn:
+		return s != measurement.Ok
+	case Dead:
+		return s == measurement.Dead
+	case Recovered:
+		return changed && s == measurement.Ok
 	}
 	return false
 }
@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultFlapWindow is the number of seconds used when `FlapWindow` is not set.
+const DefaultFlapWindow = 3600
+
+// NewFlapDetector returns a new `FlapDetector`.
+func NewFlapDetector() *FlapDetector {
+	return &FlapDetector{changes: []time.Time{}}
+}
+
+// FlapDetector counts the state changes of a monitor within a sliding window.
+//
+// A monitor is flapping while the number of changes within the window exceeds a threshold.
+// Safe for concurrent use.
+type FlapDetector struct {
+	mutex    sync.Mutex
+	changes  []time.Time
+	flapping bool
+}
+
+// Record will record a measurement taken at `at`, and return true if the monitor is flapping.
+// The second value is true if the flapping status changed with this measurement.
+func (f *FlapDetector) Record(changed bool, at time.Time, threshold int, window time.Duration) (bool, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if changed {
+		f.changes = append(f.changes, at)
+	}
+	cutoff := at.Add(-window)
+	expired := 0
+	for expired < len(f.changes) && !f.changes[expired].After(cutoff) {
+		expired++
+	}
+	f.changes = f.changes[expired:]
+
+	flapping := len(f.changes) > threshold
+	toggled := flapping != f.flapping
+	f.flapping = flapping
+	return flapping, toggled
+}
@@ -0,0 +1,32 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestFlapDetector(t *testing.T) {
+	detector := NewFlapDetector()
+	start := time.Date(2024, time.March, 29, 12, 0, 0, 0, time.UTC)
+	window := 10 * time.Minute
+
+	record := func(changed bool, minutes int) (bool, bool) {
+		return detector.Record(changed, start.Add(time.Duration(minutes)*time.Minute), 2, window)
+	}
+
+	flapping, toggled := record(true, 0)
+	debug.Assert(t, !flapping && !toggled, "expected no flapping after one change")
+	record(true, 1)
+	flapping, toggled = record(true, 2)
+	debug.Assert(t, flapping && toggled, "expected flapping to start after three changes")
+	flapping, toggled = record(false, 3)
+	debug.Assert(t, flapping && !toggled, "expected flapping to continue")
+
+	// The first change leaves the window.
+	flapping, toggled = record(false, 10)
+	debug.Assert(t, !flapping && toggled, "expected flapping to stop")
+	flapping, toggled = record(false, 11)
+	debug.Assert(t, !flapping && !toggled, "expected no change in flapping status")
+}
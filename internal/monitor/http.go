@@ -3,11 +3,17 @@ package monitor
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/jmkng/zenin/internal"
 	"github.com/jmkng/zenin/internal/measurement"
@@ -21,8 +27,10 @@ func NewHTTPProbe() HTTPProbe {
 type HTTPProbe struct{}
 
 // Poll implements `Probe.Poll` for `HTTPProbe`.
-func (h HTTPProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
-	span := measurement.NewSpan()
+//
+// The result is named so the deferred timing is applied to the returned span.
+func (h HTTPProbe) Poll(ctx context.Context, m Monitor) (span measurement.Span) {
+	span = measurement.NewSpan()
 
 	// Check remote address.
 	result, err := url.Parse(*m.RemoteAddress)
@@ -30,15 +38,13 @@ func (h HTTPProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
 		span.Downgrade(measurement.Dead, RemoteAddressInvalidMessage)
 		return span
 	}
-	// The `net.LookupHost` method will handle both IP and hostname.
-	// If you do pass a hostname and DNS resolution fails, the user will see an "invalid address" hint.
-	// This might be confusing, because the address may technically be valid -- so add another hint.
-	_, err = net.LookupHost(result.Host)
+	client, err := newHTTPClient(m, &span)
 	if err != nil {
-		span.Downgrade(measurement.Dead, RemoteAddressInvalidMessage)
-		span.Hint("Remote address DNS name resolution failed.")
+		span.Downgrade(measurement.Dead, fmt.Sprintf("Unable to configure HTTP client, %v.", err))
 		return span
 	}
+	// Each poll has its own transport, so connections are not left open between polls.
+	defer client.CloseIdleConnections()
 
 	requestBody := bytes.NewBuffer([]byte{})
 	if m.HTTPRequestBody != nil {
@@ -60,16 +66,33 @@ func (h HTTPProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
 		}
 	}
 
-	response, err := http.DefaultClient.Do(request)
+	timing := &httpTiming{}
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), timing.trace()))
+	defer timing.apply(&span)
+
+	response, err := client.Do(request)
 	if err != nil {
 		span.Downgrade(measurement.Dead)
+		var invalid x509.CertificateInvalidError
+		var redirects errTooManyRedirects
+		var lookup *net.DNSError
 		if errors.Is(err, context.DeadlineExceeded) {
 			span.Hint(TimeoutMessage)
+		} else if errors.As(err, &lookup) {
+			span.Hint(RemoteAddressInvalidMessage, "Remote address DNS name resolution failed.")
+		} else if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+			span.Hint(certificateExpiredMessage(invalid.Cert))
+		} else if errors.As(err, &redirects) {
+			span.Hint(fmt.Sprintf("Stopped after %v redirects.", int(redirects)))
 		}
 		return span
 	}
 	defer response.Body.Close()
 
+	if m.HTTPVersion != nil && *m.HTTPVersion == HTTP2 && response.ProtoMajor != 2 {
+		span.Downgrade(measurement.Dead, fmt.Sprintf("Server responded with %v, expected HTTP/2.", response.Proto))
+	}
+
 	span.HTTPStatusCode = &response.StatusCode
 	c := response.StatusCode
 
@@ -101,32 +124,303 @@ func (h HTTPProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
 		span.HTTPResponseHeaders = &headers
 	}
 
-	if m.HTTPCaptureBody != nil && *m.HTTPCaptureBody {
-		responseBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			span.Downgrade(measurement.Warn, "Response body could not be read.")
-		} else {
+	// The body is always read to completion, so the transfer can be timed.
+	var responseBody []byte
+	capture := m.HTTPCaptureBody != nil && *m.HTTPCaptureBody
+	if capture || len(m.HTTPAssertions) > 0 {
+		responseBody, err = io.ReadAll(response.Body)
+	} else {
+		_, err = io.Copy(io.Discard, response.Body)
+	}
+	timing.finish()
+	if err != nil {
+		span.Downgrade(measurement.Warn, "Response body could not be read.")
+	} else {
+		if capture {
 			s := string(responseBody)
 			span.HTTPResponseBody = &s
 		}
+		for _, v := range m.HTTPAssertions {
+			v.Evaluate(&span, responseBody)
+		}
 	}
 
 	if response.TLS != nil {
-		for _, n := range response.TLS.PeerCertificates {
-			c := measurement.Certificate{
-				Id:                 nil,
-				MeasurementId:      nil,
-				Version:            n.Version,
-				SerialNumber:       n.SerialNumber.String(),
-				PublicKeyAlgorithm: n.PublicKeyAlgorithm.String(),
-				IssuerCommonName:   n.Issuer.CommonName,
-				SubjectCommonName:  n.Subject.CommonName,
-				NotBefore:          internal.NewTimeValue(n.NotBefore),
-				NotAfter:           internal.NewTimeValue(n.NotAfter),
+		recordCertificates(&span, response.TLS.PeerCertificates)
+		checkCertificates(&span, m, response.TLS.PeerCertificates, time.Now())
+	}
+
+	return span
+}
+
+// httpTiming records the time at which each phase of an HTTP request begins and ends.
+//
+// When redirects are followed, the phases describe the last request, which is the one
+// whose response body is transferred.
+type httpTiming struct {
+	mutex        sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	done         time.Time
+}
+
+// trace returns an `httptrace.ClientTrace` that records into the `httpTiming`.
+func (t *httpTiming) trace() *httptrace.ClientTrace {
+	// Connection attempts to multiple addresses may run in parallel,
+	// only the first start and first successful connection are kept.
+	record := func(field *time.Time) {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		if field.IsZero() {
+			*field = time.Now()
+		}
+	}
+
+	return &httptrace.ClientTrace{
+		// Called at the start of every request, including redirects.
+		GetConn: func(string) { t.reset() },
+		DNSStart: func(httptrace.DNSStartInfo) { record(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			record(&t.connectStart)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				record(&t.connectDone)
 			}
-			span.Certificates = append(span.Certificates, c)
+		},
+		TLSHandshakeStart:    func() { record(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { record(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { record(&t.wroteRequest) },
+		GotFirstResponseByte: func() { record(&t.firstByte) },
+	}
+}
+
+// reset will clear the recorded phases before a new request.
+func (t *httpTiming) reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.dnsStart, t.dnsDone = time.Time{}, time.Time{}
+	t.connectStart, t.connectDone = time.Time{}, time.Time{}
+	t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+	t.wroteRequest, t.firstByte, t.done = time.Time{}, time.Time{}, time.Time{}
+}
+
+// finish marks the end of the response body transfer.
+func (t *httpTiming) finish() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.done = time.Now()
+}
+
+// apply will set the HTTP duration fields on the span for each phase that completed.
+func (t *httpTiming) apply(span *measurement.Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	between := func(start, end time.Time) *float64 {
+		if start.IsZero() || end.IsZero() {
+			return nil
 		}
+		ms := float64(end.Sub(start)) / float64(time.Millisecond)
+		return &ms
 	}
 
-	return span
+	span.HTTPDNSDuration = between(t.dnsStart, t.dnsDone)
+	span.HTTPConnectDuration = between(t.connectStart, t.connectDone)
+	span.HTTPTLSDuration = between(t.tlsStart, t.tlsDone)
+	span.HTTPFirstByteDuration = between(t.wroteRequest, t.firstByte)
+	span.HTTPTransferDuration = between(t.firstByte, t.done)
+}
+
+// defaultMaxRedirects is the number of redirects followed when `HTTPMaxRedirects` is not set.
+const defaultMaxRedirects = 10
+
+// tlsVersions maps the accepted values of `HTTPMinTLSVersion` to a TLS version.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// errTooManyRedirects is returned from `http.Client.CheckRedirect` when the redirect limit is reached.
+type errTooManyRedirects int
+
+func (e errTooManyRedirects) Error() string {
+	return fmt.Sprintf("stopped after %d redirects", int(e))
+}
+
+// newHTTPClient returns an `http.Client` configured for the monitor.
+//
+// Redirects followed by the client are recorded on the span.
+func newHTTPClient(m Monitor, span *measurement.Span) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	config := &tls.Config{}
+
+	if m.CredentialId != nil {
+		if m.Credential == nil {
+			return nil, errors.New("credential could not be loaded")
+		}
+		m.Credential.Apply(config)
+	}
+	switch {
+	case m.HTTPSkipVerify != nil && *m.HTTPSkipVerify:
+		config.InsecureSkipVerify = true
+	case m.HTTPExpiredCertMod != nil:
+		// Verification is handled by `verifyIgnoringExpiry` so that an expired certificate
+		// can be reported according to `HTTPExpiredCertMod` instead of failing the request.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = verifyIgnoringExpiry(config.RootCAs)
+	}
+	if m.HTTPMinTLSVersion != nil {
+		version, ok := tlsVersions[*m.HTTPMinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("minimum TLS version `%v` is invalid", *m.HTTPMinTLSVersion)
+		}
+		config.MinVersion = version
+	}
+	if m.HTTPVersion != nil {
+		switch *m.HTTPVersion {
+		case HTTP1:
+			// A non-nil, empty map disables HTTP/2.
+			transport.ForceAttemptHTTP2 = false
+			transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+			config.NextProtos = []string{"http/1.1"}
+		case HTTP2:
+			config.NextProtos = []string{"h2"}
+		}
+	}
+	transport.TLSClientConfig = config
+
+	if m.HTTPProxy != nil {
+		proxy, err := parseProxy(*m.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy address is invalid: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	follow := m.HTTPFollowRedirects == nil || *m.HTTPFollowRedirects
+	limit := defaultMaxRedirects
+	if m.HTTPMaxRedirects != nil {
+		limit = *m.HTTPMaxRedirects
+	}
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if !follow {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > limit {
+				return errTooManyRedirects(limit)
+			}
+			span.HTTPRedirects = append(span.HTTPRedirects, fmt.Sprintf("%v %v", request.Response.StatusCode, request.URL))
+			return nil
+		},
+	}, nil
+}
+
+// parseProxy parses a proxy address, which must use the `http`, `https` or `socks5` scheme.
+func parseProxy(address string) (*url.URL, error) {
+	proxy, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	if proxy.Host == "" {
+		return nil, errors.New("proxy host is required")
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+		return proxy, nil
+	}
+	return nil, fmt.Errorf("proxy scheme `%v` is not supported", proxy.Scheme)
+}
+
+// verifyIgnoringExpiry returns a function that verifies the peer certificate chain like the
+// default verifier, but does not reject a chain that is otherwise valid and has expired.
+//
+// If roots is nil, the system roots are used.
+func verifyIgnoringExpiry(roots *x509.CertPool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tls: no peer certificates")
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, v := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(v)
+		}
+		options := x509.VerifyOptions{DNSName: cs.ServerName, Intermediates: intermediates, Roots: roots}
+
+		_, err := cs.PeerCertificates[0].Verify(options)
+		var invalid x509.CertificateInvalidError
+		if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+			// Verify again at a time when every certificate in the chain was valid.
+			for _, v := range cs.PeerCertificates {
+				if v.NotBefore.After(options.CurrentTime) {
+					options.CurrentTime = v.NotBefore
+				}
+			}
+			_, err = cs.PeerCertificates[0].Verify(options)
+		}
+
+		return err
+	}
+}
+
+// recordCertificates will add the peer certificates to the span.
+func recordCertificates(span *measurement.Span, certificates []*x509.Certificate) {
+	for _, n := range certificates {
+		c := measurement.Certificate{
+			Id:                 nil,
+			MeasurementId:      nil,
+			Version:            n.Version,
+			SerialNumber:       n.SerialNumber.String(),
+			PublicKeyAlgorithm: n.PublicKeyAlgorithm.String(),
+			IssuerCommonName:   n.Issuer.CommonName,
+			SubjectCommonName:  n.Subject.CommonName,
+			NotBefore:          internal.NewTimeValue(n.NotBefore),
+			NotAfter:           internal.NewTimeValue(n.NotAfter),
+		}
+		span.Certificates = append(span.Certificates, c)
+	}
+}
+
+// checkCertificates will downgrade the span if any of the certificates have expired,
+// or will expire within the window set by `HTTPCertExpiryDays`.
+func checkCertificates(span *measurement.Span, m Monitor, certificates []*x509.Certificate, now time.Time) {
+	for _, v := range certificates {
+		if now.After(v.NotAfter) {
+			if m.HTTPExpiredCertMod != nil {
+				span.Downgrade(measurement.ProbeState(*m.HTTPExpiredCertMod), certificateExpiredMessage(v))
+			}
+			continue
+		}
+		if m.HTTPCertExpiryDays != nil && v.NotAfter.Before(now.AddDate(0, 0, *m.HTTPCertExpiryDays)) {
+			span.Downgrade(measurement.Warn, fmt.Sprintf("Certificate %q expires on %v.",
+				certificateSubject(v), v.NotAfter.UTC().Format(time.DateOnly)))
+		}
+	}
+}
+
+// certificateExpiredMessage returns a hint describing an expired certificate.
+func certificateExpiredMessage(c *x509.Certificate) string {
+	return fmt.Sprintf("Certificate %q expired on %v.", certificateSubject(c), c.NotAfter.UTC().Format(time.DateOnly))
+}
+
+// certificateSubject returns the common name of the certificate subject,
+// or the full subject if no common name is set.
+func certificateSubject(c *x509.Certificate) string {
+	if c.Subject.CommonName != "" {
+		return c.Subject.CommonName
+	}
+	return c.Subject.String()
 }
@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+func TestCheckCertificates(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	valid := &x509.Certificate{Subject: pkix.Name{CommonName: "valid"}, NotAfter: now.AddDate(1, 0, 0)}
+	soon := &x509.Certificate{Subject: pkix.Name{CommonName: "soon"}, NotAfter: now.AddDate(0, 0, 5)}
+	expired := &x509.Certificate{Subject: pkix.Name{CommonName: "expired"}, NotAfter: now.AddDate(0, 0, -1)}
+
+	days := 14
+	dead := string(measurement.Dead)
+
+	span := measurement.NewSpan()
+	checkCertificates(&span, Monitor{}, []*x509.Certificate{valid, soon, expired}, now)
+	debug.AssertEqual(t, span.State, measurement.Ok)
+
+	span = measurement.NewSpan()
+	checkCertificates(&span, Monitor{HTTPFields: HTTPFields{HTTPCertExpiryDays: &days}}, []*x509.Certificate{valid, soon}, now)
+	debug.AssertEqual(t, span.State, measurement.Warn)
+	debug.AssertDeepEqual(t, []string(span.StateHint), []string{`Certificate "soon" expires on 2024-07-06.`})
+
+	span = measurement.NewSpan()
+	checkCertificates(&span, Monitor{HTTPFields: HTTPFields{HTTPExpiredCertMod: &dead}}, []*x509.Certificate{valid, expired}, now)
+	debug.AssertEqual(t, span.State, measurement.Dead)
+	debug.AssertDeepEqual(t, []string(span.StateHint), []string{`Certificate "expired" expired on 2024-06-30.`})
+}
+
+func TestHTTPProbeTiming(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// A host name is used so the lookup is traced.
+	u, _ := url.Parse(server.URL)
+	method := Get
+	rng := Successful
+	skip := true
+	for _, path := range []string{"/", "/redirect"} {
+		address := "https://localhost:" + u.Port() + path
+		m := Monitor{RemoteAddress: &address, HTTPFields: HTTPFields{HTTPMethod: &method, HTTPRange: &rng, HTTPSkipVerify: &skip}}
+		span := NewHTTPProbe().Poll(context.Background(), m)
+		debug.AssertEqual(t, span.State, measurement.Ok)
+		debug.Assert(t, span.HTTPFirstByteDuration != nil, "expected first byte duration")
+		debug.Assert(t, span.HTTPTransferDuration != nil, "expected transfer duration")
+		if path == "/" {
+			debug.Assert(t, span.HTTPDNSDuration != nil, "expected DNS duration")
+			debug.Assert(t, span.HTTPConnectDuration != nil, "expected connect duration")
+			debug.Assert(t, span.HTTPTLSDuration != nil, "expected TLS duration")
+		} else {
+			// The redirected request reuses the connection of the first.
+			debug.Assert(t, span.HTTPConnectDuration == nil, "expected no connect duration for the reused connection")
+		}
+	}
+}
+
+func TestHTTPProbeRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	address := server.URL + "/a"
+	method := Get
+	monitor := func(rng HTTPRange) Monitor {
+		return Monitor{RemoteAddress: &address, HTTPFields: HTTPFields{HTTPMethod: &method, HTTPRange: &rng}}
+	}
+
+	span := NewHTTPProbe().Poll(context.Background(), monitor(Successful))
+	debug.AssertEqual(t, span.State, measurement.Ok)
+	debug.AssertDeepEqual(t, []string(span.HTTPRedirects), []string{
+		fmt.Sprintf("301 %v/b", server.URL),
+		fmt.Sprintf("302 %v/c", server.URL),
+	})
+
+	follow := false
+	m := monitor(Redirection)
+	m.HTTPFollowRedirects = &follow
+	span = NewHTTPProbe().Poll(context.Background(), m)
+	debug.AssertEqual(t, span.State, measurement.Ok)
+	debug.AssertEqual(t, len(span.HTTPRedirects), 0)
+
+	limit := 1
+	m = monitor(Successful)
+	m.HTTPMaxRedirects = &limit
+	span = NewHTTPProbe().Poll(context.Background(), m)
+	debug.AssertEqual(t, span.State, measurement.Dead)
+	debug.AssertDeepEqual(t, []string(span.StateHint), []string{"Stopped after 1 redirects."})
+}
+
+func TestHTTPProbeClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	id := 1
+	method := Get
+	rng := Successful
+	m := Monitor{
+		RemoteAddress: &server.URL,
+		CredentialId:  &id,
+		HTTPFields:    HTTPFields{HTTPMethod: &method, HTTPRange: &rng},
+	}
+
+	span := NewHTTPProbe().Poll(context.Background(), m)
+	debug.AssertEqual(t, span.State, measurement.Dead)
+
+	m.Credential = &credential.Material{RootCAs: roots}
+	span = NewHTTPProbe().Poll(context.Background(), m)
+	debug.AssertEqual(t, span.State, measurement.Dead)
+
+	m.Credential = &credential.Material{Certificate: &server.TLS.Certificates[0], RootCAs: roots}
+	span = NewHTTPProbe().Poll(context.Background(), m)
+	debug.AssertEqual(t, span.State, measurement.Ok)
+}
+
+func TestValidateHTTP2(t *testing.T) {
+	version := HTTP2
+	secure := "https://localhost"
+	plain := "http://localhost"
+
+	debug.AssertEqual(t, len(validateHTTP2(Monitor{RemoteAddress: &secure, HTTPFields: HTTPFields{HTTPVersion: &version}})), 0)
+	debug.AssertEqual(t, len(validateHTTP2(Monitor{RemoteAddress: &plain})), 0)
+	debug.AssertEqual(t, len(validateHTTP2(Monitor{RemoteAddress: &plain, HTTPFields: HTTPFields{HTTPVersion: &version}})), 1)
+}
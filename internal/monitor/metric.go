@@ -0,0 +1,196 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/pkg/exposition"
+)
+
+// metricAccept is the accept header sent by `MetricProbe`, preferring the Prometheus text format.
+const metricAccept = "text/plain;version=0.0.4;q=0.9,application/openmetrics-text;version=1.0.0;q=0.5,*/*;q=0.1"
+
+// metricReadLimit is the maximum number of bytes read from an exposition.
+const metricReadLimit = 16 << 20
+
+// NewMetricProbe returns a new `MetricProbe`
+func NewMetricProbe() MetricProbe {
+	return MetricProbe{}
+}
+
+type MetricProbe struct{}
+
+// Poll implements `Probe.Poll` for `MetricProbe`.
+func (p MetricProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
+	span := measurement.NewSpan()
+
+	// Check remote address.
+	result, err := url.Parse(*m.RemoteAddress)
+	if err != nil || result.Scheme != "http" && result.Scheme != "https" {
+		span.Downgrade(measurement.Dead, RemoteAddressInvalidMessage)
+		return span
+	}
+	selector, err := exposition.ParseSelector(*m.MetricSelector)
+	if err != nil {
+		span.Downgrade(measurement.Dead, "Metric selector is invalid.")
+		return span
+	}
+
+	client, err := newHTTPClient(m, &span)
+	if err != nil {
+		span.Downgrade(measurement.Dead, fmt.Sprintf("Unable to configure HTTP client, %v.", err))
+		return span
+	}
+	// Each poll has its own transport, so connections are not left open between polls.
+	defer client.CloseIdleConnections()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, *m.RemoteAddress, nil)
+	if err != nil {
+		span.Downgrade(measurement.Dead)
+		return span
+	}
+	request.Header.Set("Accept", metricAccept)
+	for _, pair := range m.HTTPRequestHeaders {
+		request.Header.Add(pair.Key, pair.Value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		span.Downgrade(measurement.Dead)
+		if errors.Is(err, context.DeadlineExceeded) {
+			span.Hint(TimeoutMessage)
+		} else {
+			span.Hint("Unable to fetch metrics.")
+		}
+		return span
+	}
+	defer response.Body.Close()
+	span.HTTPStatusCode = &response.StatusCode
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		span.Downgrade(measurement.Dead, fmt.Sprintf("Metrics endpoint responded with status code %v.", response.StatusCode))
+		return span
+	}
+
+	samples, err := exposition.Parse(io.LimitReader(response.Body, metricReadLimit))
+	if err != nil {
+		span.Downgrade(measurement.Dead)
+		if errors.Is(err, context.DeadlineExceeded) {
+			span.Hint(TimeoutMessage)
+		} else {
+			span.Hint(fmt.Sprintf("Metrics could not be parsed, %v.", err))
+		}
+		return span
+	}
+
+	selected := selector.Select(samples)
+	if len(selected) == 0 {
+		span.Downgrade(measurement.Dead, "Metric selector did not match any series.")
+		return span
+	}
+	if len(selected) > 1 && m.MetricAggregate == nil {
+		span.Downgrade(measurement.Dead, fmt.Sprintf("Metric selector matched %v series, set an aggregate to combine them.", len(selected)))
+		return span
+	}
+	value := aggregateMetric(m.MetricAggregate, selected)
+
+	if math.IsNaN(value) {
+		span.Downgrade(measurement.Warn, "Metric value is not a number.")
+		return span
+	}
+	if !math.IsInf(value, 0) {
+		span.MetricValue = &value
+	}
+	evaluateMetric(&span, m.MetricFields, value)
+
+	return span
+}
+
+// aggregateMetric returns the value of the samples combined by the aggregate,
+// or the value of the first sample when the aggregate is nil.
+func aggregateMetric(aggregate *MetricAggregate, samples []exposition.Sample) float64 {
+	if aggregate == nil {
+		return samples[0].Value
+	}
+
+	value := samples[0].Value
+	for _, v := range samples[1:] {
+		switch *aggregate {
+		case AggregateSum, AggregateAvg:
+			value += v.Value
+		case AggregateMin:
+			value = math.Min(value, v.Value)
+		case AggregateMax:
+			value = math.Max(value, v.Value)
+		}
+	}
+	if *aggregate == AggregateAvg {
+		value /= float64(len(samples))
+	}
+	return value
+}
+
+// evaluateMetric will downgrade the span if the value crosses the thresholds of the fields.
+func evaluateMetric(span *measurement.Span, f MetricFields, value float64) {
+	below := f.MetricCondition != nil && *f.MetricCondition == MetricBelow
+	crosses := func(threshold float64) bool {
+		if below {
+			return value < threshold
+		}
+		return value > threshold
+	}
+	direction := "above"
+	if below {
+		direction = "below"
+	}
+	formatted := strconv.FormatFloat(value, 'g', -1, 64)
+
+	if f.MetricDead != nil && crosses(*f.MetricDead) {
+		span.Downgrade(measurement.Dead, fmt.Sprintf("Metric value %v is %v the dead threshold of %v.",
+			formatted, direction, strconv.FormatFloat(*f.MetricDead, 'g', -1, 64)))
+	} else if f.MetricWarn != nil && crosses(*f.MetricWarn) {
+		span.Downgrade(measurement.Warn, fmt.Sprintf("Metric value %v is %v the warn threshold of %v.",
+			formatted, direction, strconv.FormatFloat(*f.MetricWarn, 'g', -1, 64)))
+	}
+}
+
+// validateMetric returns the validation errors of the fields of a `METRIC` monitor.
+func validateMetric(f MetricFields) []string {
+	errors := []string{}
+	if f.MetricSelector == nil {
+		errors = append(errors, "value for field `metricSelector` is required")
+	} else if _, err := exposition.ParseSelector(*f.MetricSelector); err != nil {
+		errors = append(errors, fmt.Sprintf("value for field `metricSelector` is not a valid selector: %v", err))
+	}
+	if f.MetricAggregate != nil {
+		switch *f.MetricAggregate {
+		case AggregateSum, AggregateMin, AggregateMax, AggregateAvg:
+		default:
+			errors = append(errors, "value for field `metricAggregate` must be `SUM`, `MIN`, `MAX` or `AVG`")
+		}
+	}
+	below := false
+	if f.MetricCondition != nil {
+		switch *f.MetricCondition {
+		case MetricAbove:
+		case MetricBelow:
+			below = true
+		default:
+			errors = append(errors, "value for field `metricCondition` must be `ABOVE` or `BELOW`")
+		}
+	}
+	if f.MetricWarn != nil && f.MetricDead != nil {
+		if !below && *f.MetricWarn > *f.MetricDead {
+			errors = append(errors, "value for field `metricWarn` must not be greater than `metricDead`")
+		} else if below && *f.MetricWarn < *f.MetricDead {
+			errors = append(errors, "value for field `metricWarn` must not be less than `metricDead`")
+		}
+	}
+	return errors
+}
@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/pkg/exposition"
+)
+
+const metricExposition = `# HELP job_queue_depth Number of jobs waiting in the queue.
+# TYPE job_queue_depth gauge
+job_queue_depth{queue="default"} 42
+job_queue_depth{queue="mail"} 7
+# TYPE worker_ratio gauge
+worker_ratio NaN
+`
+
+func TestMetricProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write([]byte(metricExposition))
+	}))
+	defer server.Close()
+
+	sum := AggregateSum
+	below := MetricBelow
+	float := func(v float64) *float64 { return &v }
+	cases := []struct {
+		selector  string
+		aggregate *MetricAggregate
+		condition *MetricCondition
+		warn      *float64
+		dead      *float64
+		token     bool
+		state     measurement.ProbeState
+		value     *float64
+	}{
+		{`job_queue_depth{queue="mail"}`, nil, nil, float(10), float(50), true, measurement.Ok, float(7)},
+		{`job_queue_depth{queue="default"}`, nil, nil, float(10), float(50), true, measurement.Warn, float(42)},
+		{`job_queue_depth{queue="default"}`, nil, nil, float(10), float(40), true, measurement.Dead, float(42)},
+		{`job_queue_depth{queue="default"}`, nil, &below, float(50), float(10), true, measurement.Warn, float(42)},
+		{`job_queue_depth`, &sum, nil, nil, float(45), true, measurement.Dead, float(49)},
+		// More than one series requires an aggregate.
+		{`job_queue_depth`, nil, nil, nil, nil, true, measurement.Dead, nil},
+		{`job_queue_depth{queue="other"}`, nil, nil, nil, nil, true, measurement.Dead, nil},
+		{`worker_ratio`, nil, nil, nil, nil, true, measurement.Warn, nil},
+		{`job_queue_depth{queue="mail"}`, nil, nil, nil, nil, false, measurement.Dead, nil},
+	}
+
+	for _, v := range cases {
+		m := Monitor{Kind: measurement.Metric, RemoteAddress: &server.URL}
+		m.MetricSelector = &v.selector
+		m.MetricAggregate = v.aggregate
+		m.MetricCondition = v.condition
+		m.MetricWarn = v.warn
+		m.MetricDead = v.dead
+		if v.token {
+			m.HTTPRequestHeaders = internal.PairListValue{{Key: "Authorization", Value: "Bearer secret"}}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		span := NewMetricProbe().Poll(ctx, m)
+		cancel()
+		debug.AssertEqual(t, span.State, v.state)
+		debug.AssertDeepEqual(t, span.MetricValue, v.value)
+	}
+}
+
+func TestAggregateMetric(t *testing.T) {
+	samples := []exposition.Sample{{Value: 4}, {Value: 1}, {Value: 7}}
+	cases := []struct {
+		aggregate MetricAggregate
+		expect    float64
+	}{
+		{AggregateSum, 12},
+		{AggregateMin, 1},
+		{AggregateMax, 7},
+		{AggregateAvg, 4},
+	}
+	for _, v := range cases {
+		debug.AssertEqual(t, aggregateMetric(&v.aggregate, samples), v.expect)
+	}
+	debug.AssertEqual(t, aggregateMetric(nil, samples[2:]), 7.0)
+}
+
+func TestValidateMetric(t *testing.T) {
+	selector := `queue_depth{queue=~"mail|default"}`
+	invalid := `queue_depth{queue=~"("}`
+	unknown := "MEDIAN"
+	below := MetricBelow
+	low := 10.0
+	high := 50.0
+
+	debug.AssertEqual(t, len(validateMetric(MetricFields{MetricSelector: &selector, MetricWarn: &low, MetricDead: &high})), 0)
+	debug.AssertEqual(t, len(validateMetric(MetricFields{MetricSelector: &selector, MetricCondition: &below, MetricWarn: &high, MetricDead: &low})), 0)
+
+	for i, v := range []MetricFields{
+		{},
+		{MetricSelector: &invalid},
+		{MetricSelector: &selector, MetricAggregate: &unknown},
+		{MetricSelector: &selector, MetricCondition: &unknown},
+		{MetricSelector: &selector, MetricWarn: &high, MetricDead: &low},
+		{MetricSelector: &selector, MetricCondition: &below, MetricWarn: &low, MetricDead: &high},
+	} {
+		debug.Assert(t, len(validateMetric(v)) > 0, "expected validation error for case", strconv.Itoa(i))
+	}
+}
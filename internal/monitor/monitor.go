@@ -3,14 +3,19 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/credential"
 	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/maintenance"
 	"github.com/jmkng/zenin/internal/measurement"
 	"github.com/jmkng/zenin/internal/settings"
+	"github.com/jmkng/zenin/pkg/cron"
 )
 
 const (
@@ -28,6 +33,13 @@ const (
 	ServerError   HTTPRange = "500-599"
 )
 
+type HTTPVersion = string
+
+const (
+	HTTP1 HTTPVersion = "HTTP/1.1"
+	HTTP2 HTTPVersion = "HTTP/2"
+)
+
 type HTTPMethod = string
 
 const (
@@ -51,6 +63,19 @@ type Probe interface {
 	Poll(ctx context.Context, m Monitor) measurement.Span
 }
 
+type DNSRecordType = string
+
+const (
+	RecordA     DNSRecordType = "A"
+	RecordAAAA  DNSRecordType = "AAAA"
+	RecordCNAME DNSRecordType = "CNAME"
+	RecordMX    DNSRecordType = "MX"
+	RecordTXT   DNSRecordType = "TXT"
+	RecordNS    DNSRecordType = "NS"
+	RecordSRV   DNSRecordType = "SRV"
+	RecordSOA   DNSRecordType = "SOA"
+)
+
 type ProtocolKind string
 
 const (
@@ -70,7 +95,53 @@ type Monitor struct {
 	Timeout       int                   `json:"timeout" db:"timeout"`
 	Description   *string               `json:"description" db:"description"`
 	RemoteAddress *string               `json:"remoteAddress" db:"remote_address"`
-	RemotePort    *int16                `json:"remotePort" db:"remote_port"`
+	RemotePort    *int                  `json:"remotePort" db:"remote_port"`
+	// CredentialId is the id of a credential used for TLS connections.
+	CredentialId *int `json:"credentialId" db:"credential_id"`
+	// Credential is the decrypted credential for `CredentialId`, resolved by the distributor before polling.
+	Credential *credential.Material `json:"-" db:"-"`
+	// Push is the report received by a `PUSH` monitor, set by the distributor before polling.
+	// A nil value means that no push was received in time.
+	Push *Push `json:"-" db:"-"`
+	// Cron is a cron expression used to schedule polls instead of `Interval`,
+	// which may be zero when this is set.
+	Cron *string `json:"cron" db:"cron"`
+	// CronTimezone is the IANA time zone `Cron` is evaluated in. Defaults to UTC.
+	CronTimezone *string `json:"cronTimezone" db:"cron_timezone"`
+	// RetryCount is the number of times a change to a failing state is retried, every `RetryInterval` seconds,
+	// before it is confirmed and events are started.
+	RetryCount *int `json:"retryCount" db:"retry_count"`
+	// RetryInterval is the number of seconds between retries. Defaults to `DefaultRetryInterval`.
+	RetryInterval *int `json:"retryInterval" db:"retry_interval"`
+	// Attempt is the number of unconfirmed failures before this poll, set by the distributor.
+	Attempt int `json:"-" db:"-"`
+	// FlapThreshold is the number of state changes allowed within `FlapWindow` seconds.
+	// If the monitor changes state more often, it is flapping and events are suppressed.
+	FlapThreshold *int `json:"flapThreshold" db:"flap_threshold"`
+	// FlapWindow is the number of seconds state changes are counted for. Defaults to `DefaultFlapWindow`.
+	FlapWindow *int `json:"flapWindow" db:"flap_window"`
+	// RetentionDays is the number of days measurements are kept, overriding the global setting.
+	RetentionDays *int `json:"retentionDays" db:"retention_days"`
+	// RetentionRows is the number of measurements kept, overriding the global setting.
+	RetentionRows *int `json:"retentionRows" db:"retention_rows"`
+	// Flapping is true if the most recent measurement was taken while the monitor was flapping.
+	Flapping bool `json:"flapping" db:"flapping"`
+	// Flap is the `FlapDetector` for the monitor, set by the distributor.
+	Flap *FlapDetector `json:"-" db:"-"`
+	// Maintenance is the active maintenance window, set by the distributor.
+	// Events are not started during maintenance.
+	Maintenance *maintenance.Maintenance `json:"-" db:"-"`
+	// Previous is the state of the last confirmed measurement, set by the distributor.
+	// A nil value means the monitor has no measurements.
+	Previous *measurement.ProbeState `json:"-" db:"-"`
+	// ParentId is the id of each monitor this monitor depends on.
+	// Failures are recorded as `Unreachable` while any parent is down.
+	ParentId []int `json:"parentId" db:"-"`
+	// ParentDown returns true if any parent monitor is down, set by the distributor.
+	ParentDown func() bool `json:"-" db:"-"`
+	// EventPool runs the events of the monitor, set by the distributor.
+	// Events run in their own goroutines when nil.
+	EventPool *WorkerPool `json:"-" db:"-"`
 
 	Measurements []measurement.Measurement `json:"measurements"`
 	Events       []Event                   `json:"events"`
@@ -78,6 +149,11 @@ type Monitor struct {
 	PluginFields
 	HTTPFields
 	ICMPFields
+	DNSFields
+	TCPFields
+	UDPFields
+	MetricFields
+	PushFields
 }
 
 type PluginFields struct {
@@ -95,9 +171,32 @@ type HTTPFields struct {
 	// https://pkg.go.dev/net/http#Header.Add
 	HTTPRequestHeaders internal.PairListValue `json:"httpRequestHeaders" db:"http_request_headers"`
 	HTTPRequestBody    *string                `json:"httpRequestBody" db:"http_request_body"`
-	HTTPExpiredCertMod *string                `json:"httpExpiredCertMod" db:"http_expired_cert_mod"`
-	HTTPCaptureHeaders *bool                  `json:"httpCaptureHeaders" db:"http_capture_headers"`
-	HTTPCaptureBody    *bool                  `json:"httpCaptureBody" db:"http_capture_body"`
+	// HTTPExpiredCertMod is the state applied to the measurement when a peer certificate has expired.
+	//
+	// When nil, an expired certificate fails verification and the request is not completed.
+	HTTPExpiredCertMod *string `json:"httpExpiredCertMod" db:"http_expired_cert_mod"`
+	// HTTPCertExpiryDays is a number of days. If a peer certificate expires within this window,
+	// the measurement is downgraded to a warning.
+	HTTPCertExpiryDays *int  `json:"httpCertExpiryDays" db:"http_cert_expiry_days"`
+	HTTPCaptureHeaders *bool `json:"httpCaptureHeaders" db:"http_capture_headers"`
+	HTTPCaptureBody    *bool `json:"httpCaptureBody" db:"http_capture_body"`
+	// HTTPAssertions is a list of checks performed against the response body.
+	HTTPAssertions HTTPAssertionList `json:"httpAssertions" db:"http_assertions"`
+	// HTTPFollowRedirects determines if redirects are followed. Defaults to true.
+	HTTPFollowRedirects *bool `json:"httpFollowRedirects" db:"http_follow_redirects"`
+	// HTTPMaxRedirects is the maximum number of redirects followed before the request fails.
+	// Defaults to 10.
+	HTTPMaxRedirects *int `json:"httpMaxRedirects" db:"http_max_redirects"`
+	// HTTPSkipVerify disables verification of the peer certificate chain and host name.
+	HTTPSkipVerify *bool `json:"httpSkipVerify" db:"http_skip_verify"`
+	// HTTPProxy is the URL of a proxy used for the request, such as `http://proxy:3128`.
+	// If not set, the proxy is taken from the environment.
+	HTTPProxy *string `json:"httpProxy" db:"http_proxy"`
+	// HTTPMinTLSVersion is the minimum accepted TLS version, one of `1.0`, `1.1`, `1.2` or `1.3`.
+	HTTPMinTLSVersion *string `json:"httpMinTlsVersion" db:"http_min_tls_version"`
+	// HTTPVersion forces the protocol version used for the request.
+	// If not set, HTTP/2 is used when the server supports it.
+	HTTPVersion *HTTPVersion `json:"httpVersion" db:"http_version"`
 }
 
 type ICMPFields struct {
@@ -109,6 +208,158 @@ type ICMPFields struct {
 	ICMPLossThreshold *int          `json:"icmpLossThreshold" db:"icmp_loss_threshold"`
 }
 
+type DNSFields struct {
+	DNSRecordType *DNSRecordType `json:"dnsRecordType" db:"dns_record_type"`
+	// DNSResolver is the address of the resolver to query, with an optional port.
+	//
+	// The system resolver is used when this is nil.
+	DNSResolver *string `json:"dnsResolver" db:"dns_resolver"`
+	// DNSExpectedAnswers is a set of answers that must match the answers returned by the resolver.
+	//
+	// Order is not significant. No comparison is made when this is empty.
+	DNSExpectedAnswers internal.ArrayValue `json:"dnsExpectedAnswers" db:"dns_expected_answers"`
+}
+
+// PayloadMatch determines how an expected value is compared with a response payload.
+type PayloadMatch = string
+
+const (
+	MatchSubstring PayloadMatch = "SUBSTRING"
+	MatchRegex     PayloadMatch = "REGEX"
+)
+
+type TCPFields struct {
+	// TCPSend is a payload written to the connection after it is established.
+	TCPSend *string `json:"tcpSend" db:"tcp_send"`
+	// TCPExpect is a value the response must match, according to `TCPMatch`.
+	//
+	// No response is read when this is nil.
+	TCPExpect *string `json:"tcpExpect" db:"tcp_expect"`
+	// TCPMatch determines how `TCPExpect` is compared with the response. Defaults to `SUBSTRING`.
+	TCPMatch *PayloadMatch `json:"tcpMatch" db:"tcp_match"`
+	// TCPReadLimit is the maximum number of bytes read from the response. Defaults to 4096.
+	TCPReadLimit *int `json:"tcpReadLimit" db:"tcp_read_limit"`
+	// TCPTLS determines if a TLS handshake is performed after connecting.
+	TCPTLS *bool `json:"tcpTls" db:"tcp_tls"`
+	// TCPServerName is the server name sent with the TLS handshake (SNI), and used to verify the
+	// peer certificate. Defaults to `RemoteAddress` when it is a host name.
+	TCPServerName *string `json:"tcpServerName" db:"tcp_server_name"`
+	// TCPSkipVerify disables verification of the peer certificate chain and host name.
+	TCPSkipVerify *bool `json:"tcpSkipVerify" db:"tcp_skip_verify"`
+}
+
+type UDPFields struct {
+	// UDPSend is the payload of the datagram sent to the remote address.
+	UDPSend *string `json:"udpSend" db:"udp_send"`
+	// UDPExpectReply determines if a reply must be received before the timeout.
+	// Implied when `UDPExpect` is set.
+	UDPExpectReply *bool `json:"udpExpectReply" db:"udp_expect_reply"`
+	// UDPExpect is a value the reply must match, according to `UDPMatch`.
+	UDPExpect *string `json:"udpExpect" db:"udp_expect"`
+	// UDPMatch determines how `UDPExpect` is compared with the reply. Defaults to `SUBSTRING`.
+	UDPMatch *PayloadMatch `json:"udpMatch" db:"udp_match"`
+}
+
+// MetricAggregate determines how the values of several series selected by a `METRIC` monitor are combined.
+type MetricAggregate = string
+
+const (
+	AggregateSum MetricAggregate = "SUM"
+	AggregateMin MetricAggregate = "MIN"
+	AggregateMax MetricAggregate = "MAX"
+	AggregateAvg MetricAggregate = "AVG"
+)
+
+// MetricCondition determines when the value of a `METRIC` monitor crosses a threshold.
+type MetricCondition = string
+
+const (
+	MetricAbove MetricCondition = "ABOVE"
+	MetricBelow MetricCondition = "BELOW"
+)
+
+// MetricFields configure a `METRIC` monitor, which fetches a Prometheus or OpenMetrics
+// text exposition from `RemoteAddress`.
+//
+// The request uses the HTTP request headers, proxy, TLS and credential settings of the monitor.
+type MetricFields struct {
+	// MetricSelector is a series selector such as `queue_depth{queue="mail"}`,
+	// supporting the `=`, `!=`, `=~` and `!~` label matchers.
+	MetricSelector *string `json:"metricSelector" db:"metric_selector"`
+	// MetricAggregate combines the values when more than one series is selected.
+	// When nil, the selector must match exactly one series.
+	MetricAggregate *MetricAggregate `json:"metricAggregate" db:"metric_aggregate"`
+	// MetricCondition determines if a value crosses a threshold by being above or below it.
+	// Defaults to `ABOVE`.
+	MetricCondition *MetricCondition `json:"metricCondition" db:"metric_condition"`
+	// MetricWarn is the threshold at which the measurement is downgraded to a warning.
+	MetricWarn *float64 `json:"metricWarn" db:"metric_warn"`
+	// MetricDead is the threshold at which the measurement is downgraded to dead.
+	MetricDead *float64 `json:"metricDead" db:"metric_dead"`
+}
+
+// DefaultPushGrace is the number of seconds used when `PushGrace` is not set.
+const DefaultPushGrace = 60
+
+type PushFields struct {
+	// PushToken is the secret used in the push URL of the monitor.
+	// Generated when the monitor is created.
+	PushToken *string `json:"pushToken" db:"push_token"`
+	// PushGrace is a number of seconds added to `Interval` before a missing push is recorded as `DEAD`.
+	// Defaults to `DefaultPushGrace`.
+	PushGrace *int `json:"pushGrace" db:"push_grace"`
+}
+
+// minimumWait is returned by `Monitor.Wait` when the schedule would not wait at all.
+const minimumWait = time.Minute
+
+// Wait returns the time the distributor waits from `now` until the next poll,
+// according to `Cron` if it is set, or `Interval`. Never zero, so polls can't spin.
+//
+// For `PUSH` monitors, this is the time allowed until the next push.
+func (m Monitor) Wait(now time.Time) time.Duration {
+	wait := time.Duration(m.Interval) * time.Second
+	if m.Cron != nil {
+		schedule, err := cron.Parse(*m.Cron, m.CronTimezone)
+		if err != nil {
+			env.Debug("monitor cron expression is invalid", "monitor(id)", m.Id, "error", err)
+		} else if next := schedule.Next(now); !next.IsZero() {
+			wait = next.Sub(now)
+		}
+	}
+	if wait <= 0 {
+		// A schedule that stopped matching falls back to `Interval`, which may be zero.
+		wait = minimumWait
+	}
+	if m.Kind == measurement.Push {
+		grace := DefaultPushGrace
+		if m.PushGrace != nil {
+			grace = *m.PushGrace
+		}
+		wait += time.Duration(grace) * time.Second
+	}
+	return wait
+}
+
+// DefaultRetryInterval is the number of seconds used when `RetryInterval` is not set.
+const DefaultRetryInterval = 20
+
+// RetryWait returns the time the distributor waits before retrying a failure.
+func (m Monitor) RetryWait() time.Duration {
+	if m.RetryInterval != nil {
+		return time.Duration(*m.RetryInterval) * time.Second
+	}
+	return DefaultRetryInterval * time.Second
+}
+
+// Retries returns the number of times a failure is retried before it is confirmed.
+func (m Monitor) Retries() int {
+	if m.RetryCount == nil || m.Kind == measurement.Push {
+		return 0
+	}
+	return *m.RetryCount
+}
+
 // Context returns a copy of the parent context with a timeout set according to the monitor `Timeout` field.
 func (m Monitor) Context(ctx context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(ctx, time.Duration(m.Timeout)*time.Second)
@@ -119,9 +370,11 @@ func (m Monitor) Context(ctx context.Context) (context.Context, context.CancelFu
 // This function should only ever be called on a `Monitor` from the database,
 // it requires essential fields (including id) to be populated, or it will panic.
 //
-// If the `Monitor` has events, the events will all be started in their own threads,
-// and the function will immediately return after that.
-func (m Monitor) Poll(s settings.Settings) measurement.Measurement {
+// If the `Monitor` has events, the events will all be queued on the `EventPool`,
+// and the function will return once they are queued.
+//
+// The probe and events are cancelled when `base` is done.
+func (m Monitor) Poll(base context.Context, s settings.Settings) measurement.Measurement {
 	env.Debug("poll starting", "monitor(id)", *m.Id)
 
 	var e measurement.Measurement
@@ -135,50 +388,115 @@ func (m Monitor) Poll(s settings.Settings) measurement.Measurement {
 		probe = NewHTTPProbe()
 	case measurement.TCP:
 		probe = NewTCPProbe()
+	case measurement.UDP:
+		probe = NewUDPProbe()
+	case measurement.DNS:
+		probe = NewDNSProbe()
+	case measurement.Metric:
+		probe = NewMetricProbe()
+	case measurement.Push:
+		probe = NewPushProbe(m.Push)
 	case measurement.Plugin:
 		probe = NewPluginProbe(s)
 	default:
 		panic("unrecognized probe")
 	}
 
-	ctx, cancel := m.Context(context.Background())
+	ctx, cancel := m.Context(base)
 	defer cancel()
 
 	start := time.Now()
 	span := probe.Poll(ctx, m)
 	span.Kind = m.Kind
 	duration := float64(time.Since(start)) / float64(time.Millisecond)
+	if m.Push != nil && m.Push.Duration != nil {
+		// Pushes report the duration of the job itself.
+		duration = *m.Push.Duration
+	}
 
 	e.Span = span
 	e.CreatedAt = internal.NewTimeValue(start)
 	e.UpdatedAt = internal.NewTimeValue(start)
 	e.Duration = duration
+	e.Maintenance = m.Maintenance != nil
+	if e.State == measurement.Dead && m.ParentDown != nil && m.ParentDown() {
+		e.State = measurement.Unreachable
+		e.StateHint = append(e.StateHint, "A parent monitor is down.")
+	}
+	previous := measurement.Ok
+	if m.Previous != nil {
+		previous = *m.Previous
+	}
+	// Only a change of state is retried, a failure that is already confirmed is not.
+	if e.State != measurement.Ok && e.State != measurement.Unreachable && e.State != previous &&
+		m.Attempt < m.Retries() && !e.Maintenance {
+		attempt := m.Attempt + 1
+		e.RetryAttempt = &attempt
+	}
 
 	logByState(e.State, "poll stopping", "monitor(id)", *m.Id, "duration(ms)", fmt.Sprintf("%.2f", duration),
-		"state", e.State, "hints", e.StateHint, "events", len(m.Events))
+		"state", e.State, "hints", e.StateHint, "events", len(m.Events), "retry", e.RetryAttempt != nil, "maintenance", e.Maintenance)
+	if e.RetryAttempt != nil {
+		// Events are started once the failure is confirmed.
+		return e
+	}
+	if e.Maintenance {
+		env.Debug("poll suppressed events during maintenance", "monitor(id)", *m.Id, "maintenance(id)", *m.Maintenance.Id)
+		return e
+	}
+	if e.State == measurement.Unreachable {
+		// The parent is responsible for alerting.
+		env.Debug("poll suppressed events while parent is down", "monitor(id)", *m.Id)
+		return e
+	}
+
+	// toggled is true if the flapping status changed with this measurement.
+	toggled := false
+	if m.Flap != nil && m.FlapThreshold != nil {
+		window := DefaultFlapWindow
+		if m.FlapWindow != nil {
+			window = *m.FlapWindow
+		}
+		e.Flapping, toggled = m.Flap.Record(span.State != previous, start, *m.FlapThreshold, time.Duration(window)*time.Second)
+		if toggled {
+			logByState(e.State, "flapping status changed", "monitor(id)", *m.Id, "flapping", e.Flapping)
+		}
+	}
 
 	executor := PluginExecutor{
 		Settings: s,
 		Data: struct {
 			Monitor     EventMonitor
 			Measurement EventMeasurement
-		}{Monitor: NewEventMonitor(m), Measurement: NewEventMeasurement(e)},
+			Previous    measurement.ProbeState
+			Flapping    bool
+		}{Monitor: NewEventMonitor(m), Measurement: NewEventMeasurement(e), Previous: previous, Flapping: e.Flapping},
 	}
 
 	// Start events.
 	for _, v := range m.Events {
-		if !v.IsEligible(span.State) {
+		if v.Threshold != nil && *v.Threshold == Flapping {
+			// Runs once when flapping starts, and once when it stops.
+			if !toggled {
+				continue
+			}
+		} else if e.Flapping || !v.IsEligible(m.Previous, span.State) {
 			continue
 		}
 		env.Debug("event starting", "monitor(id)", *m.Id, "event(id)", *v.Id, "plugin", *v.PluginName, "arguments", v.PluginArgs)
-		go func() {
-			ctx, cancel := m.Context(context.Background())
+		run := func() {
+			ctx, cancel := m.Context(base)
 			defer cancel()
 
 			code, stdout, stderr, dx := executor.Run(ctx, v.PluginFields)
 			hints := append(dx.Warnings, dx.Errors...)
 			logByExitCode(code, "event stopping", "monitor(id)", *m.Id, "hints", hints, "code", code, "stdout", stdout, "stderr", stderr)
-		}()
+		}
+		if m.EventPool != nil {
+			m.EventPool.Submit(run)
+		} else {
+			go run()
+		}
 	}
 
 	return e
@@ -194,10 +512,53 @@ func (m Monitor) Validate() error {
 	}
 
 	// Monitor
-	if m.Interval == 0 {
-		require("interval")
+	if m.Cron == nil {
+		if m.Interval == 0 {
+			require("interval")
+		}
+		if m.CronTimezone != nil {
+			errors = append(errors, "value for field `cron` is required with `cronTimezone`")
+		}
+	} else if schedule, err := cron.Parse(*m.Cron, m.CronTimezone); err != nil {
+		errors = append(errors, fmt.Sprintf("value for field `cron` is not a valid cron expression: %v", err))
+	} else if schedule.Next(time.Now()).IsZero() {
+		errors = append(errors, "value for field `cron` must match a time in the next five years")
+	}
+	if m.Interval < 0 {
+		errors = append(errors, "value for field `interval` must not be negative")
+	}
+	if m.RetryCount != nil {
+		if *m.RetryCount < 0 {
+			errors = append(errors, "value for field `retryCount` must not be negative")
+		} else if m.Kind == measurement.Push && *m.RetryCount > 0 {
+			errors = append(errors, "value for field `retryCount` is not supported by `PUSH` monitors")
+		}
+	}
+	if m.RetryInterval != nil && *m.RetryInterval <= 0 {
+		errors = append(errors, "value for field `retryInterval` must be greater than zero")
+	}
+	if m.FlapThreshold != nil && *m.FlapThreshold <= 0 {
+		errors = append(errors, "value for field `flapThreshold` must be greater than zero")
+	}
+	if m.FlapWindow != nil && *m.FlapWindow <= 0 {
+		errors = append(errors, "value for field `flapWindow` must be greater than zero")
 	}
-	if m.Timeout == 0 {
+	for i, v := range m.ParentId {
+		if m.Id != nil && v == *m.Id {
+			errors = append(errors, "value for field `parentId` must not contain the monitor itself")
+		}
+		if slices.Contains(m.ParentId[:i], v) {
+			errors = append(errors, "value for field `parentId` must not contain duplicates")
+		}
+	}
+	if m.RetentionDays != nil && *m.RetentionDays <= 0 {
+		errors = append(errors, "value for field `retentionDays` must be greater than zero")
+	}
+	if m.RetentionRows != nil && *m.RetentionRows <= 0 {
+		errors = append(errors, "value for field `retentionRows` must be greater than zero")
+	}
+	// Push monitors record a report instead of connecting to anything, so they don't use a timeout.
+	if m.Timeout == 0 && m.Kind != measurement.Push {
 		require("timeout")
 	}
 	if m.Name == "" {
@@ -216,10 +577,81 @@ func (m Monitor) Validate() error {
 		if m.HTTPRange == nil {
 			require("httpRange")
 		}
-	case measurement.TCP, measurement.ICMP:
+		if m.HTTPExpiredCertMod != nil &&
+			*m.HTTPExpiredCertMod != string(measurement.Warn) && *m.HTTPExpiredCertMod != string(measurement.Dead) {
+			errors = append(errors, "value for field `httpExpiredCertMod` must be `WARN` or `DEAD`")
+		}
+		if m.HTTPCertExpiryDays != nil && *m.HTTPCertExpiryDays <= 0 {
+			errors = append(errors, "value for field `httpCertExpiryDays` must be greater than zero")
+		}
+		for _, v := range m.HTTPAssertions {
+			if err := v.Validate(); err != nil {
+				errors = append(errors, err.Error())
+			}
+		}
+		if m.HTTPMaxRedirects != nil && *m.HTTPMaxRedirects < 0 {
+			errors = append(errors, "value for field `httpMaxRedirects` must not be negative")
+		}
+		if m.HTTPProxy != nil {
+			if _, err := parseProxy(*m.HTTPProxy); err != nil {
+				errors = append(errors, "value for field `httpProxy` is invalid")
+			}
+		}
+		if m.HTTPMinTLSVersion != nil {
+			if _, ok := tlsVersions[*m.HTTPMinTLSVersion]; !ok {
+				errors = append(errors, "value for field `httpMinTlsVersion` must be `1.0`, `1.1`, `1.2` or `1.3`")
+			}
+		}
+		if m.HTTPVersion != nil && *m.HTTPVersion != HTTP1 && *m.HTTPVersion != HTTP2 {
+			errors = append(errors, "value for field `httpVersion` must be `HTTP/1.1` or `HTTP/2`")
+		}
+		errors = append(errors, validateHTTP2(m)...)
+	case measurement.TCP:
 		if m.RemoteAddress == nil {
 			require("remoteAddress")
 		}
+		if m.RemotePort == nil {
+			require("remotePort")
+		} else if *m.RemotePort <= 0 || *m.RemotePort > 65535 {
+			errors = append(errors, "value for field `remotePort` must be between 1 and 65535")
+		}
+		errors = append(errors, validatePayloadMatch("tcp", m.TCPExpect, m.TCPMatch)...)
+		if m.TCPReadLimit != nil && *m.TCPReadLimit <= 0 {
+			errors = append(errors, "value for field `tcpReadLimit` must be greater than zero")
+		}
+	case measurement.UDP:
+		if m.RemoteAddress == nil {
+			require("remoteAddress")
+		}
+		if m.RemotePort == nil {
+			require("remotePort")
+		} else if *m.RemotePort <= 0 || *m.RemotePort > 65535 {
+			errors = append(errors, "value for field `remotePort` must be between 1 and 65535")
+		}
+		errors = append(errors, validatePayloadMatch("udp", m.UDPExpect, m.UDPMatch)...)
+	case measurement.ICMP:
+		if m.RemoteAddress == nil {
+			require("remoteAddress")
+		}
+	case measurement.DNS:
+		if m.RemoteAddress == nil {
+			require("remoteAddress")
+		}
+		if m.DNSRecordType == nil {
+			require("dnsRecordType")
+		} else if _, ok := dnsRecordTypes[*m.DNSRecordType]; !ok {
+			errors = append(errors, "value for field `dnsRecordType` is invalid")
+		}
+	case measurement.Metric:
+		if m.RemoteAddress == nil {
+			require("remoteAddress")
+		}
+		errors = append(errors, validateMetric(m.MetricFields)...)
+		errors = append(errors, validateHTTP2(m)...)
+	case measurement.Push:
+		if m.PushGrace != nil && *m.PushGrace < 0 {
+			errors = append(errors, "value for field `pushGrace` must not be negative")
+		}
 	case measurement.Plugin:
 		if m.PluginName == nil {
 			require("pluginName")
@@ -230,6 +662,12 @@ func (m Monitor) Validate() error {
 	name := false
 	args := false
 	for _, v := range m.Events {
+		if v.Threshold != nil && *v.Threshold != Warn && *v.Threshold != Dead && *v.Threshold != Recovered && *v.Threshold != Flapping {
+			errors = append(errors, "event threshold must be `WARN`, `DEAD`, `RECOVERED` or `FLAPPING`")
+		}
+		if v.Trigger != nil && *v.Trigger != EveryPoll && *v.Trigger != StateChange {
+			errors = append(errors, "event trigger must be `POLL` or `CHANGE`")
+		}
 		if !name {
 			if v.PluginName == nil || strings.TrimSpace(*v.PluginName) == "" {
 				errors = append(errors, "event must have a plugin name")
@@ -288,6 +726,26 @@ type PollMessage struct {
 	Monitor Monitor
 }
 
+// PushMessage is used to deliver a `Push` to an active `PUSH` monitor.
+type PushMessage struct {
+	Id   int
+	Push Push
+}
+
+// ShutdownMessage is used to stop the distributor.
+// The channel is closed once running probes and events are finished.
+type ShutdownMessage struct {
+	Done chan<- struct{}
+}
+
+// drainedMessage is sent by the distributor to itself once shutdown has finished draining.
+type drainedMessage struct{}
+
+// StatsMessage is used to request a `DistributorStats` from the distributor.
+type StatsMessage struct {
+	Reply chan<- DistributorStats
+}
+
 // logByState logs a message at an appropriate level for the provided state.
 func logByState(state measurement.ProbeState, msg string, args ...any) {
 	switch state {
@@ -311,3 +769,35 @@ func logByExitCode(code int, msg string, args ...any) {
 		env.Error(msg, args...)
 	}
 }
+
+// validateHTTP2 returns an error if HTTP/2 is forced for a remote address that does not use TLS,
+// because HTTP/2 is only negotiated during the TLS handshake.
+func validateHTTP2(m Monitor) []string {
+	if m.HTTPVersion == nil || *m.HTTPVersion != HTTP2 || m.RemoteAddress == nil {
+		return nil
+	}
+	if !strings.HasPrefix(strings.ToLower(*m.RemoteAddress), "https://") {
+		return []string{"value for field `httpVersion` must not be `HTTP/2` unless `remoteAddress` uses `https`"}
+	}
+	return nil
+}
+
+// validatePayloadMatch returns validation messages for an expected payload value and match kind.
+// The prefix is the field prefix of the probe, such as `tcp`.
+func validatePayloadMatch(prefix string, expect *string, match *PayloadMatch) []string {
+	if match == nil {
+		return nil
+	}
+	switch *match {
+	case MatchSubstring:
+	case MatchRegex:
+		if expect != nil {
+			if _, err := regexp.Compile(*expect); err != nil {
+				return []string{fmt.Sprintf("value for field `%vExpect` must be a valid expression", prefix)}
+			}
+		}
+	default:
+		return []string{fmt.Sprintf("value for field `%vMatch` must be `SUBSTRING` or `REGEX`", prefix)}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/settings"
+)
+
+func TestMonitorPollRetry(t *testing.T) {
+	// Nothing is listening on the port once the listener is closed.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	id, retries := 1, 2
+	m := newTCPMonitor(t, address)
+	m.Id = &id
+	m.Timeout = 1
+	m.RetryCount = &retries
+
+	first, second := 1, 2
+	for attempt, expect := range []*int{&first, &second, nil} {
+		m.Attempt = attempt
+		e := m.Poll(context.Background(), settings.Settings{})
+		debug.AssertEqual(t, e.State, measurement.Dead)
+		debug.AssertDeepEqual(t, e.RetryAttempt, expect)
+	}
+}
+
+func TestMonitorPollUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	id, retries := 1, 2
+	m := newTCPMonitor(t, address)
+	m.Id = &id
+	m.Timeout = 1
+	m.RetryCount = &retries
+	m.ParentDown = func() bool { return true }
+
+	e := m.Poll(context.Background(), settings.Settings{})
+	debug.AssertEqual(t, e.State, measurement.Unreachable)
+	debug.Assert(t, e.RetryAttempt == nil, "unreachable measurement should not be retried")
+
+	m.ParentDown = func() bool { return false }
+	e = m.Poll(context.Background(), settings.Settings{})
+	debug.AssertEqual(t, e.State, measurement.Dead)
+}
+
+func TestEventIsEligible(t *testing.T) {
+	threshold := func(v EventThreshold) *EventThreshold { return &v }
+	trigger := func(v EventTrigger) *EventTrigger { return &v }
+	state := func(v measurement.ProbeState) *measurement.ProbeState { return &v }
+
+	cases := []struct {
+		event    Event
+		previous *measurement.ProbeState
+		current  measurement.ProbeState
+		expect   bool
+	}{
+		{Event{}, state(measurement.Ok), measurement.Ok, true},
+		{Event{Threshold: threshold(Warn)}, state(measurement.Dead), measurement.Dead, true},
+		{Event{Threshold: threshold(Dead)}, state(measurement.Ok), measurement.Warn, false},
+		{Event{Threshold: threshold(Dead), Trigger: trigger(StateChange)}, state(measurement.Dead), measurement.Dead, false},
+		{Event{Threshold: threshold(Dead), Trigger: trigger(StateChange)}, state(measurement.Warn), measurement.Dead, true},
+		{Event{Threshold: threshold(Dead), Trigger: trigger(StateChange)}, nil, measurement.Dead, true},
+		{Event{Trigger: trigger(StateChange)}, nil, measurement.Ok, false},
+		{Event{Trigger: trigger(StateChange)}, state(measurement.Dead), measurement.Ok, true},
+		{Event{Threshold: threshold(Recovered)}, state(measurement.Dead), measurement.Ok, true},
+		{Event{Threshold: threshold(Recovered)}, state(measurement.Ok), measurement.Ok, false},
+		{Event{Threshold: threshold(Recovered)}, state(measurement.Ok), measurement.Dead, false},
+	}
+
+	for i, v := range cases {
+		debug.Assert(t, v.event.IsEligible(v.previous, v.current) == v.expect, "unexpected result for case", strconv.Itoa(i))
+	}
+}
@@ -5,18 +5,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"text/template"
+	"time"
 
 	"github.com/jmkng/zenin/internal/env"
 	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/metrics"
 	"github.com/jmkng/zenin/internal/settings"
 )
 
+// pluginWaitDelay is the time allowed for a killed plugin to release its output streams.
+const pluginWaitDelay = 2 * time.Second
+
 // NewPluginProbe returns a new `PluginProbe`
 func NewPluginProbe(settings settings.Settings) PluginProbe {
 	return PluginProbe{Settings: settings}
@@ -102,7 +106,7 @@ func (p PluginExecutor) Run(ctx context.Context, f PluginFields) (int, string, s
 		}
 	}
 
-	cmd := exec.CommandContext(ctx, path, args...)
+	name := path
 	ext := filepath.Ext(*f.PluginName)
 
 	switch runtime.GOOS {
@@ -110,12 +114,10 @@ func (p PluginExecutor) Run(ctx context.Context, f PluginFields) (int, string, s
 		switch ext {
 		// powershell -File <path> ...
 		case ".ps1":
-			args = append([]string{"-File", path}, args...)
-			cmd = exec.Command("powershell", args...)
+			name, args = "powershell", append([]string{"-File", path}, args...)
 		// cmd /c <path> ...
 		case ".bat":
-			args = append([]string{"/c", path}, args...)
-			cmd = exec.Command("cmd", args...)
+			name, args = "cmd", append([]string{"/c", path}, args...)
 		}
 	case "darwin", "linux":
 		switch ext {
@@ -126,19 +128,17 @@ func (p PluginExecutor) Run(ctx context.Context, f PluginFields) (int, string, s
 				dx.Error("Shell environment variable is not accessible.")
 				return code, stdout, stderr, dx
 			}
-			args := append([]string{path}, args...)
-			cmd = exec.Command(shell, args...)
+			name, args = shell, append([]string{path}, args...)
 		}
 	}
 
-	stdoutPipe, stdoutPipeErr := cmd.StdoutPipe()
-	if stdoutPipeErr != nil {
-		dx.Warn("Failed to access plugin output stream.")
-	}
-	stderrPipe, stderrPipeErr := cmd.StderrPipe()
-	if stderrPipeErr != nil {
-		dx.Warn("Failed to access plugin error stream.")
-	}
+	// The plugin and anything it starts are killed when the context is done.
+	cmd := exec.CommandContext(ctx, name, args...)
+	killProcessGroup(cmd)
+	cmd.WaitDelay = pluginWaitDelay
+	var stdoutBuffer, stderrBuffer bytes.Buffer
+	cmd.Stdout = &stdoutBuffer
+	cmd.Stderr = &stderrBuffer
 
 	// Start plugin.
 	if err := cmd.Start(); err != nil {
@@ -146,29 +146,21 @@ func (p PluginExecutor) Run(ctx context.Context, f PluginFields) (int, string, s
 		return code, stdout, stderr, dx
 	}
 
-	if stdoutPipeErr == nil {
-		b, err := io.ReadAll(stdoutPipe)
-		if err != nil {
-			dx.Warn("Failed to read plugin output stream.")
-		} else {
-			stdout = string(bytes.TrimSpace(b))
-		}
-	}
-	if stderrPipeErr == nil {
-		b, err := io.ReadAll(stderrPipe)
-		if err != nil {
-			dx.Warn("Failed to read plugin error stream.")
-		} else {
-			stderr = string(bytes.TrimSpace(b))
-		}
-	}
-
 	// Wait for execution, collect output.
 	err = cmd.Wait()
+	stdout = string(bytes.TrimSpace(stdoutBuffer.Bytes()))
+	stderr = string(bytes.TrimSpace(stderrBuffer.Bytes()))
+	result := "ok"
+	defer func() { metrics.Default.Inc(metrics.PluginExecutions, "result", result) }()
 	if err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result = "timeout"
 			dx.Error(TimeoutMessage)
 			return code, stdout, stderr, dx
+		} else if errors.Is(ctx.Err(), context.Canceled) {
+			result = "stopped"
+			dx.Error("Plugin was stopped before it finished.")
+			return code, stdout, stderr, dx
 		} else if exit, ok := err.(*exec.ExitError); ok {
 			code = exit.ExitCode()
 
@@ -176,11 +168,14 @@ func (p PluginExecutor) Run(ctx context.Context, f PluginFields) (int, string, s
 			case 0:
 				break
 			case 1:
+				result = "warn"
 				dx.Warn("Plugin returned a warn exit code.")
 			default:
+				result = "dead"
 				dx.Error("Plugin returned a dead exit code.")
 			}
 		} else {
+			result = "error"
 			dx.Error("Failed to execute plugin.")
 		}
 	}
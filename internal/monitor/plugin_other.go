@@ -0,0 +1,9 @@
+//go:build !unix
+
+package monitor
+
+import "os/exec"
+
+// killProcessGroup is a no-op on platforms without process groups.
+// Only the plugin process is killed when the command is cancelled.
+func killProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package monitor
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup will start the command in a new process group, and kill the whole
+// group when the command is cancelled, so children of a plugin are not orphaned.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultProbeWorkers is the number of probes that may run at once when not configured.
+	DefaultProbeWorkers = 64
+	// DefaultEventWorkers is the number of events that may run at once when not configured.
+	DefaultEventWorkers = 16
+	// queueFactor is multiplied by the number of workers to find the capacity of a queue.
+	queueFactor = 8
+)
+
+// NewWorkerPool returns a new `WorkerPool` with the number of workers started.
+//
+// The queue holds up to `queueFactor` jobs per worker.
+func NewWorkerPool(workers int) *WorkerPool {
+	if workers <= 0 {
+		workers = 1
+	}
+	p := &WorkerPool{
+		workers: workers,
+		jobs:    make(chan func(), workers*queueFactor),
+	}
+	p.group.Add(workers)
+	for range workers {
+		go p.work()
+	}
+	return p
+}
+
+// WorkerPool runs jobs on a fixed number of goroutines.
+// Safe for concurrent use.
+type WorkerPool struct {
+	workers int
+	jobs    chan func()
+	group   sync.WaitGroup
+
+	// The number of jobs currently running.
+	active atomic.Int64
+	// The number of jobs dropped because the queue was full.
+	dropped atomic.Uint64
+}
+
+// TrySubmit will queue the job, and return false if the queue is full.
+func (p *WorkerPool) TrySubmit(job func()) bool {
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		p.dropped.Add(1)
+		return false
+	}
+}
+
+// Submit will queue the job, blocking until the queue has room.
+func (p *WorkerPool) Submit(job func()) {
+	p.jobs <- job
+}
+
+// Stats returns a snapshot of the pool.
+func (p *WorkerPool) Stats() PoolStats {
+	return PoolStats{
+		Workers:  p.workers,
+		Capacity: cap(p.jobs),
+		Queued:   len(p.jobs),
+		Active:   p.active.Load(),
+		Dropped:  p.dropped.Load(),
+	}
+}
+
+// Close will stop accepting jobs and block until the queued jobs are finished.
+func (p *WorkerPool) Close() {
+	close(p.jobs)
+	p.group.Wait()
+}
+
+func (p *WorkerPool) work() {
+	defer p.group.Done()
+	for job := range p.jobs {
+		p.active.Add(1)
+		job()
+		p.active.Add(-1)
+	}
+}
+
+// PoolStats describes the load on a `WorkerPool`.
+type PoolStats struct {
+	// The number of workers.
+	Workers int `json:"workers"`
+	// The maximum number of queued jobs.
+	Capacity int `json:"capacity"`
+	// The number of jobs waiting for a worker.
+	Queued int `json:"queued"`
+	// The number of jobs currently running.
+	Active int64 `json:"active"`
+	// The number of jobs dropped because the queue was full.
+	Dropped uint64 `json:"dropped"`
+}
+
+// DistributorStats describes the load on a `Distributor`.
+type DistributorStats struct {
+	// The number of monitors being polled.
+	Monitors int `json:"monitors"`
+	// The number of connected feed subscribers.
+	Subscribers int `json:"subscribers"`
+	// Probe executions.
+	Probes PoolStats `json:"probes"`
+	// Event executions.
+	Events PoolStats `json:"events"`
+}
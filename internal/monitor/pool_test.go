@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestWorkerPool(t *testing.T) {
+	pool := NewWorkerPool(1)
+
+	// Hold the only worker so jobs are queued behind it.
+	started := make(chan struct{})
+	release := make(chan struct{})
+	debug.Assert(t, pool.TrySubmit(func() {
+		close(started)
+		<-release
+	}), "expected first job to be accepted")
+	<-started
+
+	var group sync.WaitGroup
+	for range queueFactor {
+		group.Add(1)
+		debug.Assert(t, pool.TrySubmit(group.Done), "expected job to be queued")
+	}
+	debug.Assert(t, !pool.TrySubmit(func() {}), "expected full queue to drop the job")
+
+	stats := pool.Stats()
+	debug.AssertEqual(t, stats.Workers, 1)
+	debug.AssertEqual(t, stats.Capacity, queueFactor)
+	debug.AssertEqual(t, stats.Queued, queueFactor)
+	debug.AssertEqual(t, stats.Active, int64(1))
+	debug.AssertEqual(t, stats.Dropped, uint64(1))
+
+	close(release)
+	group.Wait()
+	pool.Close()
+
+	stats = pool.Stats()
+	debug.AssertEqual(t, stats.Queued, 0)
+	debug.AssertEqual(t, stats.Active, int64(0))
+}
@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// Push is a report received by a `PUSH` monitor.
+type Push struct {
+	// State is the state reported by the job. Defaults to `OK`.
+	State measurement.ProbeState
+	// Message is an optional message, recorded as a hint.
+	Message *string
+	// Duration is the optional duration of the job in milliseconds.
+	Duration *float64
+}
+
+// NewPushProbe returns a new `PushProbe`
+func NewPushProbe(push *Push) PushProbe {
+	return PushProbe{push: push}
+}
+
+// PushProbe does not probe anything, it records the `Push` received by the monitor.
+type PushProbe struct {
+	push *Push
+}
+
+// Poll implements `Probe.Poll` for `PushProbe`.
+func (p PushProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
+	span := measurement.NewSpan()
+	if p.push == nil {
+		span.Downgrade(measurement.Dead, "No push was received in time.")
+		return span
+	}
+
+	if p.push.State != "" {
+		span.Downgrade(p.push.State)
+	}
+	if p.push.Message != nil {
+		span.Hint(*p.push.Message)
+	}
+
+	return span
+}
+
+// NewPushToken returns a random token used in the push URL of a monitor.
+func NewPushToken() (string, error) {
+	bytes, err := env.GetRandomBytes(24)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
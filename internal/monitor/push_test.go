@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+func TestPushProbe(t *testing.T) {
+	span := NewPushProbe(nil).Poll(context.Background(), Monitor{})
+	debug.AssertEqual(t, span.State, measurement.Dead)
+
+	message := "Backup finished with warnings."
+	span = NewPushProbe(&Push{State: measurement.Warn, Message: &message}).Poll(context.Background(), Monitor{})
+	debug.AssertEqual(t, span.State, measurement.Warn)
+	debug.AssertDeepEqual(t, []string(span.StateHint), []string{message})
+
+	span = NewPushProbe(&Push{State: measurement.Ok}).Poll(context.Background(), Monitor{})
+	debug.AssertEqual(t, span.State, measurement.Ok)
+}
+
+func TestMonitorWait(t *testing.T) {
+	grace := 30
+	m := Monitor{Interval: 300, Kind: measurement.HTTP}
+	debug.AssertEqual(t, m.Wait(time.Now()), 300*time.Second)
+	m.Kind = measurement.Push
+	debug.AssertEqual(t, m.Wait(time.Now()), 300*time.Second+DefaultPushGrace*time.Second)
+	m.PushGrace = &grace
+	debug.AssertEqual(t, m.Wait(time.Now()), 330*time.Second)
+
+	cron := "0 * * * *"
+	m = Monitor{Kind: measurement.HTTP, Cron: &cron}
+	debug.AssertEqual(t, m.Wait(time.Date(2024, time.March, 29, 17, 30, 0, 0, time.UTC)), 30*time.Minute)
+}
+
+func TestMonitorWaitNeverFires(t *testing.T) {
+	never := "0 0 30 2 *"
+	m := Monitor{Kind: measurement.HTTP, Cron: &never}
+	debug.AssertEqual(t, m.Wait(time.Now()), minimumWait)
+	debug.Assert(t, m.Validate() != nil, "expected error for schedule that never fires")
+}
+
+func TestPushValidate(t *testing.T) {
+	m := Monitor{Name: "Backup", Kind: measurement.Push, Interval: 3600}
+	err := m.Validate()
+	debug.Assert(t, err == nil, "expected push monitor without timeout to be valid")
+}
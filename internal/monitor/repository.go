@@ -21,7 +21,8 @@ type MonitorRepository interface {
 
 // SelectMonitorParams is a set of parameters used to narrow the scope of the `SelectMonitor` repository method.
 //
-// If the `Id` property is not nil, it will take priority over all other parameters.
+// If the `Id` property is not nil, it will take priority over all other parameters,
+// followed by `PushToken`.
 // Otherwise, all parameters that are not nil are applied to the query.
 //
 // Implements `Injectable.Inject`, so it can automatically apply suitable SQL to
@@ -33,6 +34,10 @@ type SelectMonitorParams struct {
 
 	// Group 2 -----
 
+	PushToken *string
+
+	// Group 3 -----
+
 	Active *bool
 	Kind   *measurement.ProbeKind
 }
@@ -45,6 +50,11 @@ func (s SelectMonitorParams) Inject(builder *sql.Builder) {
 		builder.Push(")")
 		return
 	}
+	if s.PushToken != nil {
+		builder.Push(fmt.Sprintf("%v push_token = ", builder.Where()))
+		builder.BindString(*s.PushToken)
+		return
+	}
 	where := builder.Where()
 	if s.Active != nil {
 		x := fmt.Sprintf("%v ACTIVE = ", where)
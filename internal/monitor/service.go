@@ -4,10 +4,12 @@ import (
 	"context"
 	"io/fs"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/jmkng/zenin/internal"
 	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/measurement"
 )
 
 // NewMonitorService returns a new `MonitorService`.
@@ -40,6 +42,17 @@ func (s MonitorService) CreateMonitor(ctx context.Context, monitor Monitor) (int
 	if err := monitor.Validate(); err != nil {
 		return -1, internal.TimestampValue{}, err
 	}
+	if err := s.validateParents(ctx, monitor); err != nil {
+		return -1, internal.TimestampValue{}, err
+	}
+	monitor.PushToken = nil
+	if monitor.Kind == measurement.Push {
+		token, err := NewPushToken()
+		if err != nil {
+			return -1, internal.TimestampValue{}, err
+		}
+		monitor.PushToken = &token
+	}
 
 	time := internal.NewTimeValue(time.Now())
 	monitor.CreatedAt = time
@@ -70,6 +83,12 @@ func (s MonitorService) UpdateMonitor(ctx context.Context, monitor Monitor) (int
 	if err := monitor.Validate(); err != nil {
 		return internal.TimestampValue{}, err
 	}
+	if err := s.validateParents(ctx, monitor); err != nil {
+		return internal.TimestampValue{}, err
+	}
+	if err := s.keepPushToken(ctx, &monitor); err != nil {
+		return internal.TimestampValue{}, err
+	}
 
 	time := internal.NewTimeValue(time.Now())
 	monitor.UpdatedAt = time
@@ -100,6 +119,98 @@ func (s MonitorService) UpdateMonitor(ctx context.Context, monitor Monitor) (int
 	}, nil
 }
 
+// DeleteMonitor will delete the monitors and stop polling them.
+//
+// Active monitors that depend on a deleted monitor are restarted, so they no longer wait on it.
+func (s MonitorService) DeleteMonitor(ctx context.Context, id []int) error {
+	active, err := s.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+	dependents := []int{}
+	for _, v := range active {
+		if !slices.Contains(id, *v.Id) && slices.ContainsFunc(v.ParentId, func(p int) bool { return slices.Contains(id, p) }) {
+			dependents = append(dependents, *v.Id)
+		}
+	}
+
+	if err := s.Repository.DeleteMonitor(ctx, id); err != nil {
+		return err
+	}
+	for _, v := range id {
+		s.Distributor <- StopMessage{Id: v}
+	}
+	if len(dependents) == 0 {
+		return nil
+	}
+
+	restarted, err := s.Repository.SelectMonitor(ctx, 0, &SelectMonitorParams{Id: &dependents})
+	if err != nil {
+		return err
+	}
+	for _, v := range restarted {
+		s.Distributor <- StopMessage{Id: *v.Id}
+		s.Distributor <- StartMessage{Monitor: v}
+	}
+	return nil
+}
+
+// keepPushToken will assign the stored push token to a `PUSH` monitor, so the push URL
+// does not change when the monitor is updated. A token is generated if none is stored.
+//
+// Monitors of other kinds have their token removed.
+func (s MonitorService) keepPushToken(ctx context.Context, monitor *Monitor) error {
+	if monitor.Kind != measurement.Push {
+		monitor.PushToken = nil
+		return nil
+	}
+
+	found, err := s.Repository.SelectMonitor(ctx, 0, &SelectMonitorParams{Id: &[]int{*monitor.Id}})
+	if err != nil {
+		return err
+	}
+	if len(found) > 0 && found[0].PushToken != nil {
+		monitor.PushToken = found[0].PushToken
+		return nil
+	}
+
+	token, err := NewPushToken()
+	if err != nil {
+		return err
+	}
+	monitor.PushToken = &token
+	return nil
+}
+
+// GetPushMonitor returns the active `PUSH` monitor with the push token.
+// Returns false if no such monitor exists.
+func (s MonitorService) GetPushMonitor(ctx context.Context, token string) (Monitor, bool, error) {
+	found, err := s.Repository.SelectMonitor(ctx, 0, &SelectMonitorParams{PushToken: &token})
+	if err != nil {
+		return Monitor{}, false, err
+	}
+	if len(found) == 0 || found[0].Kind != measurement.Push || !found[0].Active {
+		return Monitor{}, false, nil
+	}
+	return found[0], true, nil
+}
+
+// GetDistributorStats returns the current load on the distributor.
+func (s MonitorService) GetDistributorStats(ctx context.Context) (DistributorStats, error) {
+	reply := make(chan DistributorStats, 1)
+	select {
+	case s.Distributor <- StatsMessage{Reply: reply}:
+	case <-ctx.Done():
+		return DistributorStats{}, ctx.Err()
+	}
+	select {
+	case stats := <-reply:
+		return stats, nil
+	case <-ctx.Done():
+		return DistributorStats{}, ctx.Err()
+	}
+}
+
 func (m MonitorService) GetPlugins() ([]string, error) {
 	var plugins []string
 	root := env.Env.PluginsDir
@@ -1,13 +1,23 @@
 package monitor
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
 	"net"
+	"regexp"
 	"strconv"
 
 	"github.com/jmkng/zenin/internal/measurement"
 )
 
+// defaultTCPReadLimit is the number of bytes read when `TCPReadLimit` is not set.
+const defaultTCPReadLimit = 4096
+
 // NewTCPProbe returns a new `TCPProbe`
 func NewTCPProbe() TCPProbe {
 	return TCPProbe{}
@@ -18,7 +28,7 @@ type TCPProbe struct{}
 // Poll implements `Probe.Poll` for `TCPProbe`.
 func (i TCPProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
 	span := measurement.NewSpan()
-	address := net.JoinHostPort(*m.RemoteAddress, strconv.Itoa(int(*m.RemotePort)))
+	address := net.JoinHostPort(*m.RemoteAddress, strconv.Itoa(*m.RemotePort))
 
 	// Check remote address.
 	_, err := net.ResolveTCPAddr("tcp", address)
@@ -46,5 +56,127 @@ func (i TCPProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
 	}
 	defer conn.Close()
 
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if m.TCPTLS != nil && *m.TCPTLS {
+		config, err := newTCPTLSConfig(m)
+		if err != nil {
+			span.Downgrade(measurement.Dead, fmt.Sprintf("Unable to configure TLS, %v.", err))
+			return span
+		}
+		client := tls.Client(conn, config)
+		if err := client.HandshakeContext(ctx); err != nil {
+			span.Downgrade(measurement.Dead, "TLS handshake failed.")
+			var invalid *tls.CertificateVerificationError
+			var expired x509.CertificateInvalidError
+			if errors.As(err, &invalid) {
+				recordCertificates(&span, invalid.UnverifiedCertificates)
+			}
+			if errors.As(err, &expired) && expired.Reason == x509.Expired {
+				span.Hint(certificateExpiredMessage(expired.Cert))
+			} else if isTimeout(err) {
+				span.Hint(TimeoutMessage)
+			}
+			return span
+		}
+		recordCertificates(&span, client.ConnectionState().PeerCertificates)
+		conn = client
+	}
+
+	if m.TCPSend != nil {
+		if _, err := conn.Write([]byte(*m.TCPSend)); err != nil {
+			span.Downgrade(measurement.Dead, "Unable to send payload.")
+			if isTimeout(err) {
+				span.Hint(TimeoutMessage)
+			}
+			return span
+		}
+	}
+
+	if m.TCPExpect != nil {
+		match, err := newPayloadMatcher(*m.TCPExpect, m.TCPMatch)
+		if err != nil {
+			span.Downgrade(measurement.Dead, fmt.Sprintf("Expression %q is invalid.", *m.TCPExpect))
+			return span
+		}
+		limit := defaultTCPReadLimit
+		if m.TCPReadLimit != nil {
+			limit = *m.TCPReadLimit
+		}
+
+		response, err := readUntil(conn, match, limit)
+		if !match(response) {
+			span.Downgrade(measurement.Dead, fmt.Sprintf("Response did not match %q, observed %q.", *m.TCPExpect, truncate(string(response))))
+			if isTimeout(err) {
+				span.Hint(TimeoutMessage)
+			}
+		}
+	}
+
 	return span
 }
+
+// newTCPTLSConfig returns a `tls.Config` for a TLS handshake with the monitor.
+func newTCPTLSConfig(m Monitor) (*tls.Config, error) {
+	config := &tls.Config{}
+	if m.TCPServerName != nil {
+		config.ServerName = *m.TCPServerName
+	} else if net.ParseIP(*m.RemoteAddress) == nil {
+		config.ServerName = *m.RemoteAddress
+	}
+	if m.TCPSkipVerify != nil && *m.TCPSkipVerify {
+		config.InsecureSkipVerify = true
+	} else if config.ServerName == "" {
+		return nil, errors.New("server name is required to verify the certificate of an IP address")
+	}
+	if m.CredentialId != nil {
+		if m.Credential == nil {
+			return nil, errors.New("credential could not be loaded")
+		}
+		m.Credential.Apply(config)
+	}
+
+	return config, nil
+}
+
+// newPayloadMatcher returns a function that reports if a response matches the expected value.
+func newPayloadMatcher(expect string, kind *PayloadMatch) (func([]byte) bool, error) {
+	if kind != nil && *kind == MatchRegex {
+		expression, err := regexp.Compile(expect)
+		if err != nil {
+			return nil, err
+		}
+		return expression.Match, nil
+	}
+
+	return func(response []byte) bool {
+		return bytes.Contains(response, []byte(expect))
+	}, nil
+}
+
+// readUntil reads from the reader until the response matches, the limit is reached, or
+// the reader returns an error. Returns the bytes read and the error, if any.
+func readUntil(reader io.Reader, match func([]byte) bool, limit int) ([]byte, error) {
+	response := make([]byte, 0, min(limit, 512))
+	buffer := make([]byte, 512)
+	for len(response) < limit {
+		n, err := reader.Read(buffer[:min(len(buffer), limit-len(response))])
+		response = append(response, buffer[:n]...)
+		if match(response) {
+			return response, nil
+		}
+		if err != nil {
+			return response, err
+		}
+	}
+
+	return response, nil
+}
+
+// isTimeout returns true if the error is a network timeout.
+func isTimeout(err error) bool {
+	var e net.Error
+	return errors.As(err, &e) && e.Timeout() || errors.Is(err, context.DeadlineExceeded)
+}
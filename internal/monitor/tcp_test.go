@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// newTCPMonitor returns a TCP `Monitor` for the listener address.
+func newTCPMonitor(t *testing.T, address string) Monitor {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number, _ := strconv.Atoi(port)
+	return Monitor{Kind: measurement.TCP, RemoteAddress: &host, RemotePort: &number}
+}
+
+func TestTCPProbeExpect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte("+OK ready\r\n"))
+				line, _ := bufio.NewReader(conn).ReadString('\n')
+				if line == "PING\r\n" {
+					conn.Write([]byte("+PONG\r\n"))
+				}
+			}()
+		}
+	}()
+
+	send := "PING\r\n"
+	cases := []struct {
+		expect string
+		match  PayloadMatch
+		state  measurement.ProbeState
+	}{
+		{"+PONG", MatchSubstring, measurement.Ok},
+		{"-ERR", MatchSubstring, measurement.Dead},
+		{`^\+OK ready\r\n\+PONG`, MatchRegex, measurement.Ok},
+	}
+	for _, v := range cases {
+		m := newTCPMonitor(t, listener.Addr().String())
+		m.TCPSend = &send
+		m.TCPExpect = &v.expect
+		m.TCPMatch = &v.match
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		span := NewTCPProbe().Poll(ctx, m)
+		cancel()
+		debug.AssertEqual(t, span.State, v.state)
+	}
+}
+
+func TestTCPProbeTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	enable := true
+	name := "example.com"
+	send := "GET / HTTP/1.0\r\n\r\n"
+	expect := "200 OK"
+	m := newTCPMonitor(t, server.Listener.Addr().String())
+	m.TCPTLS = &enable
+	m.TCPServerName = &name
+	m.TCPSend = &send
+	m.TCPExpect = &expect
+
+	// The certificate is signed by an unknown authority.
+	span := NewTCPProbe().Poll(context.Background(), m)
+	debug.AssertEqual(t, span.State, measurement.Dead)
+	debug.AssertEqual(t, len(span.Certificates), 1)
+
+	id := 1
+	m.CredentialId = &id
+	m.Credential = &credential.Material{RootCAs: roots}
+	span = NewTCPProbe().Poll(context.Background(), m)
+	debug.AssertEqual(t, span.State, measurement.Ok)
+	debug.AssertEqual(t, len(span.Certificates), 1)
+}
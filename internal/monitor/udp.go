@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// udpGracePeriod is the time spent waiting for an ICMP error when no reply is expected.
+const udpGracePeriod = 500 * time.Millisecond
+
+// NewUDPProbe returns a new `UDPProbe`
+func NewUDPProbe() UDPProbe {
+	return UDPProbe{}
+}
+
+type UDPProbe struct{}
+
+// Poll implements `Probe.Poll` for `UDPProbe`.
+func (u UDPProbe) Poll(ctx context.Context, m Monitor) measurement.Span {
+	span := measurement.NewSpan()
+	address := net.JoinHostPort(*m.RemoteAddress, strconv.Itoa(*m.RemotePort))
+
+	// Check remote address.
+	_, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		span.Downgrade(measurement.Dead, RemoteAddressInvalidMessage)
+		return span
+	}
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		span.Downgrade(measurement.Dead, "Unable to establish connection.")
+		return span
+	}
+	defer conn.Close()
+
+	// When no reply is expected, only wait long enough to receive an ICMP error.
+	expectReply := m.UDPExpect != nil || m.UDPExpectReply != nil && *m.UDPExpectReply
+	deadline, ok := ctx.Deadline()
+	if grace := time.Now().Add(udpGracePeriod); !expectReply && (!ok || grace.Before(deadline)) {
+		deadline = grace
+		ok = true
+	}
+	if ok {
+		conn.SetDeadline(deadline)
+	}
+
+	payload := []byte{}
+	if m.UDPSend != nil {
+		payload = []byte(*m.UDPSend)
+	}
+	if _, err := conn.Write(payload); err != nil {
+		span.Downgrade(measurement.Dead, "Unable to send payload.")
+		return span
+	}
+
+	// The maximum size of a UDP payload.
+	buffer := make([]byte, 65535)
+	n, err := conn.Read(buffer)
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		// The host responded with ICMP port unreachable, so nothing is listening on the port.
+		span.Downgrade(measurement.Dead, "Remote port is unreachable.")
+	case isTimeout(err):
+		if expectReply {
+			span.Downgrade(measurement.Dead, "No reply was received.", TimeoutMessage)
+		}
+	case err != nil:
+		span.Downgrade(measurement.Dead, "Unable to receive reply.")
+	case m.UDPExpect != nil:
+		match, err := newPayloadMatcher(*m.UDPExpect, m.UDPMatch)
+		if err != nil {
+			span.Downgrade(measurement.Dead, fmt.Sprintf("Expression %q is invalid.", *m.UDPExpect))
+			return span
+		}
+		if !match(buffer[:n]) {
+			span.Downgrade(measurement.Dead, fmt.Sprintf("Reply did not match %q, observed %q.", *m.UDPExpect, truncate(string(buffer[:n]))))
+		}
+	}
+
+	return span
+}
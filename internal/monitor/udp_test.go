@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// newUDPMonitor returns a UDP `Monitor` for the address.
+func newUDPMonitor(t *testing.T, address string) Monitor {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number, _ := strconv.Atoi(port)
+	return Monitor{Kind: measurement.UDP, RemoteAddress: &host, RemotePort: &number}
+}
+
+func TestUDPProbe(t *testing.T) {
+	// Replies to "ping" with "pong", and ignores everything else.
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go func() {
+		buffer := make([]byte, 512)
+		for {
+			n, address, err := server.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if string(buffer[:n]) == "ping" {
+				server.WriteTo([]byte("pong"), address)
+			}
+		}
+	}()
+
+	// Find a port with nothing listening.
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddress := closed.LocalAddr().String()
+	closed.Close()
+
+	ping := "ping"
+	other := "other"
+	pong := "pong"
+	reply := true
+	cases := []struct {
+		address string
+		send    *string
+		reply   *bool
+		expect  *string
+		state   measurement.ProbeState
+		hint    string
+	}{
+		{server.LocalAddr().String(), &ping, nil, &pong, measurement.Ok, ""},
+		{server.LocalAddr().String(), &ping, nil, &other, measurement.Dead, `Reply did not match "other", observed "pong".`},
+		{server.LocalAddr().String(), &other, &reply, nil, measurement.Dead, "No reply was received."},
+		{server.LocalAddr().String(), &other, nil, nil, measurement.Ok, ""},
+		{closedAddress, &ping, nil, nil, measurement.Dead, "Remote port is unreachable."},
+		{closedAddress, &ping, &reply, nil, measurement.Dead, "Remote port is unreachable."},
+	}
+	for _, v := range cases {
+		m := newUDPMonitor(t, v.address)
+		m.UDPSend = v.send
+		m.UDPExpectReply = v.reply
+		m.UDPExpect = v.expect
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		span := NewUDPProbe().Poll(ctx, m)
+		cancel()
+		debug.AssertEqual(t, span.State, v.state)
+		if v.hint != "" {
+			debug.AssertEqual(t, span.StateHint[0], v.hint)
+		}
+	}
+}
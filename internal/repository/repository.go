@@ -2,8 +2,12 @@ package repository
 
 import (
 	"github.com/jmkng/zenin/internal/account"
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/internal/maintenance"
 	"github.com/jmkng/zenin/internal/measurement"
 	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/retention"
+	"github.com/jmkng/zenin/internal/rollup"
 	"github.com/jmkng/zenin/internal/settings"
 )
 
@@ -15,6 +19,11 @@ var SchemaTables = []string{
 	"certificate",
 	"settings",
 	"event",
+	"credential",
+	"monitor_dependency",
+	"measurement_rollup",
+	"maintenance_monitor",
+	"maintenance",
 }
 
 type Repository interface {
@@ -27,9 +36,15 @@ type Repository interface {
 	Fixture() error
 	// Describe returns a description as key/value pairs.
 	Describe() []any
+	// Close will close the repository.
+	Close() error
 
 	monitor.MonitorRepository
 	measurement.MeasurementRepository
 	account.AccountRepository
 	settings.SettingsRepository
+	credential.CredentialRepository
+	maintenance.MaintenanceRepository
+	retention.RetentionRepository
+	rollup.RollupRepository
 }
@@ -0,0 +1,17 @@
+package retention
+
+import (
+	"context"
+
+	"github.com/jmkng/zenin/internal"
+)
+
+// RetentionRepository is a type used to delete expired rows from the measurement tables.
+type RetentionRepository interface {
+	// DeleteMeasurementBefore deletes up to `limit` of the oldest measurements of the monitor
+	// created before the cutoff, and returns the number deleted.
+	DeleteMeasurementBefore(ctx context.Context, id int, cutoff internal.TimeValue, limit int) (int, error)
+	// DeleteMeasurementBeyond deletes up to `limit` measurements of the monitor that are not
+	// among the newest `keep`, and returns the number deleted.
+	DeleteMeasurementBeyond(ctx context.Context, id int, keep int, limit int) (int, error)
+}
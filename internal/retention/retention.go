@@ -0,0 +1,57 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/settings"
+)
+
+// Policy determines which measurements of a monitor are kept.
+// Measurements are kept forever when both fields are nil.
+type Policy struct {
+	// The number of days measurements are kept.
+	Days *int
+	// The number of measurements kept.
+	Rows *int
+}
+
+// NewPolicy returns the `Policy` for the monitor.
+// Each field of the monitor overrides the global setting.
+func NewPolicy(s settings.Settings, m monitor.Monitor) Policy {
+	policy := Policy{Days: s.RetentionDays, Rows: s.RetentionRows}
+	if m.RetentionDays != nil {
+		policy.Days = m.RetentionDays
+	}
+	if m.RetentionRows != nil {
+		policy.Rows = m.RetentionRows
+	}
+	return policy
+}
+
+// IsEmpty returns true if the `Policy` keeps all measurements.
+func (p Policy) IsEmpty() bool {
+	return p.Days == nil && p.Rows == nil
+}
+
+// Cutoff returns the time before which measurements expire, or nil if they don't expire by age.
+func (p Policy) Cutoff(now time.Time) *internal.TimeValue {
+	if p.Days == nil {
+		return nil
+	}
+	cutoff := internal.NewTimeValue(now.AddDate(0, 0, -*p.Days))
+	return &cutoff
+}
+
+// Run describes a pruning run.
+type Run struct {
+	StartedAt internal.TimeValue `json:"startedAt"`
+	StoppedAt internal.TimeValue `json:"stoppedAt"`
+	// The number of monitors with a retention policy.
+	Monitors int `json:"monitors"`
+	// The number of measurements deleted.
+	Deleted int `json:"deleted"`
+	// The error that stopped the run, if any.
+	Error *string `json:"error"`
+}
@@ -0,0 +1,26 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/settings"
+)
+
+func TestNewPolicy(t *testing.T) {
+	days, rows, override := 30, 1000, 7
+
+	policy := NewPolicy(settings.Settings{}, monitor.Monitor{})
+	debug.Assert(t, policy.IsEmpty(), "expected empty policy without settings")
+
+	global := settings.Settings{RetentionDays: &days, RetentionRows: &rows}
+	policy = NewPolicy(global, monitor.Monitor{RetentionDays: &override})
+	debug.AssertEqual(t, *policy.Days, override)
+	debug.AssertEqual(t, *policy.Rows, rows)
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	debug.AssertEqual(t, policy.Cutoff(now).Time(), time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC))
+	debug.Assert(t, Policy{Rows: &rows}.Cutoff(now) == nil, "expected no cutoff without days")
+}
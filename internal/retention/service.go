@@ -0,0 +1,202 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/metrics"
+	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/settings"
+)
+
+const (
+	// Interval is the time between pruning runs.
+	Interval = time.Hour
+	// BatchSize is the maximum number of rows deleted by one statement.
+	BatchSize = 500
+	// batchPause is the time waited between batches, so inserts are not starved of the database.
+	batchPause = 50 * time.Millisecond
+	// startDelay is the time waited before the first run after startup.
+	startDelay = time.Minute
+	// history is the number of runs remembered.
+	history = 24
+)
+
+// NewRetentionService returns a new `RetentionService`.
+func NewRetentionService(r RetentionRepository, m monitor.MonitorRepository, s settings.SettingsRepository) RetentionService {
+	return RetentionService{
+		Repository: r,
+		Monitor:    m,
+		Settings:   s,
+		state:      &state{trigger: make(chan struct{}, 1)},
+	}
+}
+
+// RetentionService is a service used to prune expired measurements.
+type RetentionService struct {
+	Repository RetentionRepository
+	Monitor    monitor.MonitorRepository
+	Settings   settings.SettingsRepository
+
+	state *state
+}
+
+type state struct {
+	mutex   sync.Mutex
+	running bool
+	runs    []Run
+	trigger chan struct{}
+}
+
+// Start will prune expired measurements on an interval in a new goroutine,
+// until the context is done. The returned channel is closed once the goroutine has stopped.
+func (s RetentionService) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait := startDelay
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			case <-s.state.trigger:
+			}
+			s.Prune(ctx)
+			wait = Interval
+		}
+	}()
+	return done
+}
+
+// Trigger will start a pruning run in the background as soon as possible.
+// Returns false if a run is already waiting to start.
+func (s RetentionService) Trigger() bool {
+	select {
+	case s.state.trigger <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetRuns returns the most recent pruning runs, newest first.
+func (s RetentionService) GetRuns() []Run {
+	s.state.mutex.Lock()
+	defer s.state.mutex.Unlock()
+
+	runs := make([]Run, 0, len(s.state.runs))
+	for i := len(s.state.runs) - 1; i >= 0; i-- {
+		runs = append(runs, s.state.runs[i])
+	}
+	return runs
+}
+
+// Prune will delete the expired measurements of every monitor in batches, and return a `Run`.
+func (s RetentionService) Prune(ctx context.Context) Run {
+	s.state.mutex.Lock()
+	if s.state.running {
+		s.state.mutex.Unlock()
+		return Run{}
+	}
+	s.state.running = true
+	s.state.mutex.Unlock()
+
+	start := time.Now()
+	run := Run{StartedAt: internal.NewTimeValue(start)}
+	env.Debug("retention run starting")
+
+	err := s.prune(ctx, &run)
+	run.StoppedAt = internal.NewTimeValue(time.Now())
+	duration := time.Since(start).Milliseconds()
+	if err != nil {
+		message := err.Error()
+		run.Error = &message
+		metrics.Default.Inc(metrics.RepositoryErrors, "operation", "retention")
+		env.Error("retention run failed", "monitors", run.Monitors, "deleted", run.Deleted, "duration(ms)", duration, "error", err)
+	} else {
+		env.Info("retention run finished", "monitors", run.Monitors, "deleted", run.Deleted, "duration(ms)", duration)
+	}
+
+	s.state.mutex.Lock()
+	s.state.runs = append(s.state.runs, run)
+	if len(s.state.runs) > history {
+		s.state.runs = s.state.runs[len(s.state.runs)-history:]
+	}
+	s.state.running = false
+	s.state.mutex.Unlock()
+
+	return run
+}
+
+func (s RetentionService) prune(ctx context.Context, run *Run) error {
+	global, err := s.Settings.SelectSettings(ctx)
+	if err != nil {
+		return err
+	}
+	monitors, err := s.Monitor.SelectMonitor(ctx, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, v := range monitors {
+		policy := NewPolicy(global, v)
+		if policy.IsEmpty() {
+			continue
+		}
+		run.Monitors++
+
+		deleted := 0
+		if cutoff := policy.Cutoff(now); cutoff != nil {
+			n, err := s.batch(ctx, func() (int, error) {
+				return s.Repository.DeleteMeasurementBefore(ctx, *v.Id, *cutoff, BatchSize)
+			})
+			deleted += n
+			if err != nil {
+				run.Deleted += deleted
+				return err
+			}
+		}
+		if policy.Rows != nil {
+			n, err := s.batch(ctx, func() (int, error) {
+				return s.Repository.DeleteMeasurementBeyond(ctx, *v.Id, *policy.Rows, BatchSize)
+			})
+			deleted += n
+			if err != nil {
+				run.Deleted += deleted
+				return err
+			}
+		}
+
+		run.Deleted += deleted
+		if deleted > 0 {
+			env.Debug("retention pruned monitor", "monitor(id)", *v.Id, "deleted", deleted)
+		}
+	}
+
+	return nil
+}
+
+// batch will call the delete function until it deletes less than a full batch,
+// pausing between calls. Returns the total number of rows deleted.
+func (s RetentionService) batch(ctx context.Context, delete func() (int, error)) (int, error) {
+	total := 0
+	for {
+		n, err := delete()
+		total += n
+		if err != nil || n < BatchSize {
+			return total, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return total, errors.New("retention run was cancelled")
+		case <-time.After(batchPause):
+		}
+	}
+}
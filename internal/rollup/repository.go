@@ -0,0 +1,49 @@
+package rollup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/pkg/sql"
+)
+
+// RollupRepository is a type used to interact with the measurement rollup table.
+type RollupRepository interface {
+	// SelectRollup returns the rollups of the monitor at the resolution, ordered by bucket.
+	SelectRollup(ctx context.Context, id int, resolution Resolution, params *SelectRollupParams) ([]Rollup, error)
+	// SelectRollupLatest returns the start of the newest bucket of the monitor at the resolution,
+	// or nil if there are none.
+	SelectRollupLatest(ctx context.Context, id int, resolution Resolution) (*internal.TimeValue, error)
+	// SelectRollupOrigin returns the creation time of the oldest measurement of the monitor
+	// created at or after `from`, or nil if there are none. Retry attempts are ignored.
+	SelectRollupOrigin(ctx context.Context, id int, from internal.TimeValue) (*internal.TimeValue, error)
+	// SelectRollupSample returns a `Sample` for each measurement of the monitor created
+	// at or after `from` and before `to`, ordered by creation time. Retry attempts are ignored.
+	SelectRollupSample(ctx context.Context, id int, from internal.TimeValue, to internal.TimeValue) ([]Sample, error)
+	// UpsertRollup inserts the rollups, replacing any with the same monitor, resolution and bucket.
+	UpsertRollup(ctx context.Context, rollups []Rollup) error
+}
+
+// SelectRollupParams is a set of parameters used to narrow the scope of the `SelectRollup`
+// repository method.
+//
+// Implements `Injectable.Inject`, so it can automatically apply suitable SQL to
+// a `sql.Builder`.
+type SelectRollupParams struct {
+	After  *internal.TimeValue
+	Before *internal.TimeValue
+}
+
+// Inject implements `Injectable.Inject` for `SelectRollupParams`.
+func (s SelectRollupParams) Inject(builder *sql.Builder) {
+	where := builder.Where()
+	if s.After != nil {
+		builder.Push(fmt.Sprintf("%v bucket >= ", where))
+		builder.BindOpaque(s.After)
+	}
+	if s.Before != nil {
+		builder.Push(fmt.Sprintf("%v bucket < ", where))
+		builder.BindOpaque(s.Before)
+	}
+}
@@ -0,0 +1,289 @@
+package rollup
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// Resolution is the width of the buckets that measurements are rolled into.
+type Resolution string
+
+const (
+	Hour Resolution = "HOUR"
+	Day  Resolution = "DAY"
+)
+
+const (
+	// HourRange is the widest time range answered from raw measurements.
+	// Wider ranges are answered from hourly rollups.
+	HourRange = 2 * 24 * time.Hour
+	// DayRange is the widest time range answered from hourly rollups.
+	// Wider ranges are answered from daily rollups.
+	DayRange = 60 * 24 * time.Hour
+)
+
+// ResolutionFromString returns an equivalent `Resolution` from the provided string.
+// The value parameter is normalized to lowercase.
+func ResolutionFromString(value string) (Resolution, error) {
+	switch strings.ToLower(value) {
+	case "hour":
+		return Hour, nil
+	case "day":
+		return Day, nil
+	default:
+		return "", errors.New("invalid rollup resolution")
+	}
+}
+
+// ResolutionFor returns the `Resolution` suitable for a time range,
+// or nil if the range should be answered from raw measurements.
+func ResolutionFor(after time.Time, before time.Time) *Resolution {
+	var resolution Resolution
+	switch span := before.Sub(after); {
+	case span <= HourRange:
+		return nil
+	case span <= DayRange:
+		resolution = Hour
+	default:
+		resolution = Day
+	}
+	return &resolution
+}
+
+// Truncate returns the start of the bucket containing the time, in UTC.
+func (r Resolution) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch r {
+	case Day:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	default:
+		return t.Truncate(time.Hour)
+	}
+}
+
+// Next returns the start of the bucket following the bucket starting at the time.
+func (r Resolution) Next(t time.Time) time.Time {
+	switch r {
+	case Day:
+		return t.AddDate(0, 0, 1)
+	default:
+		return t.Add(time.Hour)
+	}
+}
+
+// Rollup is a summary of the measurements of a monitor taken within one bucket.
+//
+// Measurements taken during a maintenance window are only counted by `Maintenance`.
+// Retry attempts are not included.
+type Rollup struct {
+	MonitorId  int                `json:"monitorId" db:"monitor_id"`
+	Resolution Resolution         `json:"resolution" db:"resolution"`
+	Bucket     internal.TimeValue `json:"bucket" db:"bucket"`
+	// The number of measurements by state.
+	Ok          int `json:"ok" db:"count_ok"`
+	Warn        int `json:"warn" db:"count_warn"`
+	Dead        int `json:"dead" db:"count_dead"`
+	Unreachable int `json:"unreachable" db:"count_unreachable"`
+	Maintenance int `json:"maintenance" db:"count_maintenance"`
+	// Duration statistics in milliseconds.
+	DurationMin float64 `json:"durationMin" db:"duration_min"`
+	DurationAvg float64 `json:"durationAvg" db:"duration_avg"`
+	DurationMax float64 `json:"durationMax" db:"duration_max"`
+	DurationP95 float64 `json:"durationP95" db:"duration_p95"`
+	DurationP99 float64 `json:"durationP99" db:"duration_p99"`
+	// The mean average round trip time of ICMP measurements.
+	ICMPAvgRTT *float64 `json:"icmpAvgRtt" db:"icmp_avg_rtt"`
+	// The mean packet loss of ICMP measurements, as a percentage.
+	ICMPLoss *float64 `json:"icmpLoss" db:"icmp_loss"`
+}
+
+// Count returns the number of measurements outside of maintenance windows.
+func (r Rollup) Count() int {
+	return r.Ok + r.Warn + r.Dead + r.Unreachable
+}
+
+// Sample is the part of a `Measurement` that is rolled up.
+type Sample struct {
+	CreatedAt      internal.TimeValue     `db:"created_at"`
+	State          measurement.ProbeState `db:"state"`
+	Duration       float64                `db:"duration"`
+	ICMPPacketsIn  *int                   `db:"icmp_packets_in"`
+	ICMPPacketsOut *int                   `db:"icmp_packets_out"`
+	ICMPAvgRTT     *float64               `db:"icmp_avg_rtt"`
+	Maintenance    bool                   `db:"maintenance"`
+}
+
+// Aggregate returns a `Rollup` for each bucket of the resolution that contains a sample,
+// ordered by bucket.
+func Aggregate(id int, resolution Resolution, samples []Sample) []Rollup {
+	buckets := map[time.Time][]Sample{}
+	for _, v := range samples {
+		bucket := resolution.Truncate(v.CreatedAt.Time())
+		buckets[bucket] = append(buckets[bucket], v)
+	}
+
+	rollups := make([]Rollup, 0, len(buckets))
+	for bucket, samples := range buckets {
+		rollup := summarize(samples)
+		rollup.MonitorId = id
+		rollup.Resolution = resolution
+		rollup.Bucket = internal.NewTimeValue(bucket)
+		rollups = append(rollups, rollup)
+	}
+	slices.SortFunc(rollups, func(a, b Rollup) int {
+		return a.Bucket.Time().Compare(b.Bucket.Time())
+	})
+	return rollups
+}
+
+func summarize(samples []Sample) Rollup {
+	var rollup Rollup
+	durations := []float64{}
+	var rtt, loss []float64
+
+	for _, v := range samples {
+		if v.Maintenance {
+			rollup.Maintenance++
+			continue
+		}
+		switch v.State {
+		case measurement.Ok:
+			rollup.Ok++
+		case measurement.Warn:
+			rollup.Warn++
+		case measurement.Dead:
+			rollup.Dead++
+		case measurement.Unreachable:
+			rollup.Unreachable++
+		}
+		durations = append(durations, v.Duration)
+		if v.ICMPAvgRTT != nil {
+			rtt = append(rtt, *v.ICMPAvgRTT)
+		}
+		if v.ICMPPacketsIn != nil && v.ICMPPacketsOut != nil && *v.ICMPPacketsOut > 0 {
+			lost := float64(*v.ICMPPacketsOut-*v.ICMPPacketsIn) / float64(*v.ICMPPacketsOut)
+			loss = append(loss, math.Max(lost, 0)*100)
+		}
+	}
+
+	if len(durations) > 0 {
+		slices.Sort(durations)
+		rollup.DurationMin = durations[0]
+		rollup.DurationMax = durations[len(durations)-1]
+		rollup.DurationAvg = mean(durations)
+		rollup.DurationP95 = Percentile(durations, 95)
+		rollup.DurationP99 = Percentile(durations, 99)
+	}
+	if len(rtt) > 0 {
+		avg := mean(rtt)
+		rollup.ICMPAvgRTT = &avg
+	}
+	if len(loss) > 0 {
+		avg := mean(loss)
+		rollup.ICMPLoss = &avg
+	}
+	return rollup
+}
+
+// Combine returns a `Rollup` of the resolution for each bucket that contains one of the
+// narrower rollups, ordered by bucket. Used to build daily rollups from hourly rollups,
+// so they don't depend on measurements that retention may have deleted.
+//
+// Percentiles can't be combined exactly, so they are averaged by measurement count.
+func Combine(id int, resolution Resolution, rollups []Rollup) []Rollup {
+	buckets := map[time.Time][]Rollup{}
+	for _, v := range rollups {
+		bucket := resolution.Truncate(v.Bucket.Time())
+		buckets[bucket] = append(buckets[bucket], v)
+	}
+
+	combined := make([]Rollup, 0, len(buckets))
+	for bucket, rollups := range buckets {
+		rollup := merge(rollups)
+		rollup.MonitorId = id
+		rollup.Resolution = resolution
+		rollup.Bucket = internal.NewTimeValue(bucket)
+		combined = append(combined, rollup)
+	}
+	slices.SortFunc(combined, func(a, b Rollup) int {
+		return a.Bucket.Time().Compare(b.Bucket.Time())
+	})
+	return combined
+}
+
+func merge(rollups []Rollup) Rollup {
+	var rollup Rollup
+	// The number of measurements, and the number weighing each ICMP average.
+	var count, rttCount, lossCount float64
+	var rtt, loss float64
+
+	for _, v := range rollups {
+		rollup.Ok += v.Ok
+		rollup.Warn += v.Warn
+		rollup.Dead += v.Dead
+		rollup.Unreachable += v.Unreachable
+		rollup.Maintenance += v.Maintenance
+
+		n := float64(v.Count())
+		if n == 0 {
+			continue
+		}
+		if count == 0 || v.DurationMin < rollup.DurationMin {
+			rollup.DurationMin = v.DurationMin
+		}
+		rollup.DurationMax = math.Max(rollup.DurationMax, v.DurationMax)
+		rollup.DurationAvg += v.DurationAvg * n
+		rollup.DurationP95 += v.DurationP95 * n
+		rollup.DurationP99 += v.DurationP99 * n
+		count += n
+
+		if v.ICMPAvgRTT != nil {
+			rtt += *v.ICMPAvgRTT * n
+			rttCount += n
+		}
+		if v.ICMPLoss != nil {
+			loss += *v.ICMPLoss * n
+			lossCount += n
+		}
+	}
+
+	if count > 0 {
+		rollup.DurationAvg /= count
+		rollup.DurationP95 /= count
+		rollup.DurationP99 /= count
+	}
+	if rttCount > 0 {
+		avg := rtt / rttCount
+		rollup.ICMPAvgRTT = &avg
+	}
+	if lossCount > 0 {
+		avg := loss / lossCount
+		rollup.ICMPLoss = &avg
+	}
+	return rollup
+}
+
+// Percentile returns the nearest-rank percentile of the sorted values,
+// or zero if there are no values.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
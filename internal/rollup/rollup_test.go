@@ -0,0 +1,118 @@
+package rollup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+func TestAggregate(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	in, out, rtt := 3, 4, 10.0
+
+	samples := []Sample{}
+	for i := range 100 {
+		samples = append(samples, Sample{
+			CreatedAt: internal.NewTimeValue(base.Add(time.Duration(i) * 30 * time.Second)),
+			State:     measurement.Ok,
+			Duration:  float64(i + 1),
+		})
+	}
+	samples = append(samples,
+		Sample{CreatedAt: internal.NewTimeValue(base.Add(90 * time.Minute)), State: measurement.Dead,
+			ICMPPacketsIn: &in, ICMPPacketsOut: &out, ICMPAvgRTT: &rtt},
+		Sample{CreatedAt: internal.NewTimeValue(base.Add(91 * time.Minute)), State: measurement.Dead,
+			Duration: 5000, Maintenance: true},
+	)
+
+	hourly := Aggregate(1, Hour, samples)
+	debug.AssertEqual(t, len(hourly), 2)
+
+	first := hourly[0]
+	debug.AssertEqual(t, first.Bucket.Time(), base)
+	debug.AssertEqual(t, first.Ok, 100)
+	debug.AssertEqual(t, first.Count(), 100)
+	debug.AssertEqual(t, first.DurationMin, 1.0)
+	debug.AssertEqual(t, first.DurationMax, 100.0)
+	debug.AssertEqual(t, first.DurationAvg, 50.5)
+	debug.AssertEqual(t, first.DurationP95, 95.0)
+	debug.AssertEqual(t, first.DurationP99, 99.0)
+	debug.Assert(t, first.ICMPAvgRTT == nil, "expected no icmp round trip time")
+
+	second := hourly[1]
+	debug.AssertEqual(t, second.Bucket.Time(), base.Add(time.Hour))
+	debug.AssertEqual(t, second.Dead, 1)
+	debug.AssertEqual(t, second.Maintenance, 1)
+	debug.AssertEqual(t, second.DurationMax, 0.0)
+	debug.AssertEqual(t, *second.ICMPAvgRTT, rtt)
+	debug.AssertEqual(t, *second.ICMPLoss, 25.0)
+
+	daily := Aggregate(1, Day, samples)
+	debug.AssertEqual(t, len(daily), 1)
+	debug.AssertEqual(t, daily[0].Bucket.Time(), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+	debug.AssertEqual(t, daily[0].Count(), 101)
+}
+
+func TestCombine(t *testing.T) {
+	base := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	rtt, loss := 10.0, 25.0
+
+	hourly := []Rollup{
+		{Bucket: internal.NewTimeValue(base), Ok: 3, Maintenance: 1,
+			DurationMin: 5, DurationAvg: 10, DurationMax: 20, DurationP95: 20, DurationP99: 20},
+		{Bucket: internal.NewTimeValue(base.Add(23 * time.Hour)), Ok: 0, Dead: 1,
+			DurationMin: 30, DurationAvg: 30, DurationMax: 30, DurationP95: 30, DurationP99: 30,
+			ICMPAvgRTT: &rtt, ICMPLoss: &loss},
+		// Only maintenance, so the durations are ignored.
+		{Bucket: internal.NewTimeValue(base.Add(12 * time.Hour)), Maintenance: 2},
+		{Bucket: internal.NewTimeValue(base.Add(24 * time.Hour)), Warn: 2,
+			DurationMin: 1, DurationAvg: 2, DurationMax: 3, DurationP95: 3, DurationP99: 3},
+	}
+
+	daily := Combine(1, Day, hourly)
+	debug.AssertEqual(t, len(daily), 2)
+
+	first := daily[0]
+	debug.AssertEqual(t, first.Resolution, Day)
+	debug.AssertEqual(t, first.Bucket.Time(), base)
+	debug.AssertEqual(t, first.Count(), 4)
+	debug.AssertEqual(t, first.Maintenance, 3)
+	debug.AssertEqual(t, first.DurationMin, 5.0)
+	debug.AssertEqual(t, first.DurationMax, 30.0)
+	debug.AssertEqual(t, first.DurationAvg, 15.0)
+	debug.AssertEqual(t, first.DurationP95, 22.5)
+	debug.AssertEqual(t, *first.ICMPAvgRTT, rtt)
+	debug.AssertEqual(t, *first.ICMPLoss, loss)
+
+	second := daily[1]
+	debug.AssertEqual(t, second.Bucket.Time(), base.AddDate(0, 0, 1))
+	debug.AssertEqual(t, second.Warn, 2)
+	debug.AssertEqual(t, second.DurationMin, 1.0)
+	debug.Assert(t, second.ICMPAvgRTT == nil, "expected no icmp round trip time")
+}
+
+func TestPercentile(t *testing.T) {
+	debug.AssertEqual(t, Percentile(nil, 95), 0.0)
+	debug.AssertEqual(t, Percentile([]float64{7}, 99), 7.0)
+	debug.AssertEqual(t, Percentile([]float64{1, 2, 3, 4}, 50), 2.0)
+	debug.AssertEqual(t, Percentile([]float64{1, 2, 3, 4}, 95), 4.0)
+}
+
+func TestResolutionFor(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	debug.Assert(t, ResolutionFor(now.AddDate(0, 0, -1), now) == nil, "expected raw measurements for one day")
+	debug.AssertEqual(t, *ResolutionFor(now.AddDate(0, 0, -7), now), Hour)
+	debug.AssertEqual(t, *ResolutionFor(now.AddDate(-1, 0, 0), now), Day)
+}
+
+func TestResolutionTruncate(t *testing.T) {
+	at := time.Date(2024, 3, 10, 12, 34, 56, 0, time.UTC)
+
+	debug.AssertEqual(t, Hour.Truncate(at), time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC))
+	debug.AssertEqual(t, Day.Truncate(at), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+	debug.AssertEqual(t, Day.Next(Day.Truncate(at)), time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,179 @@
+package rollup
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/metrics"
+	"github.com/jmkng/zenin/internal/monitor"
+)
+
+const (
+	// Interval is the time between aggregation runs.
+	Interval = 15 * time.Minute
+	// settle is the time waited after a bucket ends before it is aggregated,
+	// so measurements that are still being recorded are included.
+	settle = 2 * time.Minute
+	// window is the widest time range of samples loaded at once.
+	window = 24 * time.Hour
+	// startDelay is the time waited before the first run after startup.
+	startDelay = 30 * time.Second
+)
+
+// NewRollupService returns a new `RollupService`.
+func NewRollupService(r RollupRepository, m monitor.MonitorRepository) RollupService {
+	return RollupService{Repository: r, Monitor: m}
+}
+
+// RollupService is a service used to aggregate measurements into hourly rollups,
+// and hourly rollups into daily rollups.
+type RollupService struct {
+	Repository RollupRepository
+	Monitor    monitor.MonitorRepository
+}
+
+// Start will aggregate measurements on an interval in a new goroutine,
+// until the context is done. The returned channel is closed once the goroutine has stopped.
+func (s RollupService) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait := startDelay
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			if err := s.Aggregate(ctx, time.Now()); err != nil {
+				metrics.Default.Inc(metrics.RepositoryErrors, "operation", "rollup")
+				env.Error("rollup run failed", "error", err)
+			}
+			wait = Interval
+		}
+	}()
+	return done
+}
+
+// Aggregate will roll the measurements of every monitor into each complete bucket
+// that was not yet aggregated.
+//
+// A bucket is complete when it ended at least `settle` before `now`.
+func (s RollupService) Aggregate(ctx context.Context, now time.Time) error {
+	monitors, err := s.Monitor.SelectMonitor(ctx, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range monitors {
+		// Daily rollups are combined from the hourly rollups, so those are stored first.
+		for _, resolution := range []Resolution{Hour, Day} {
+			var stored int
+			if resolution == Day {
+				stored, err = s.combine(ctx, *v.Id, now)
+			} else {
+				stored, err = s.aggregate(ctx, *v.Id, resolution, now)
+			}
+			if err != nil {
+				return err
+			}
+			if stored > 0 {
+				env.Debug("rollup aggregated monitor", "monitor(id)", *v.Id, "resolution", resolution, "buckets", stored)
+			}
+		}
+	}
+	return nil
+}
+
+// aggregate will aggregate the measurements in the complete buckets of the monitor
+// at the resolution, and return the number of rollups stored.
+func (s RollupService) aggregate(ctx context.Context, id int, resolution Resolution, now time.Time) (int, error) {
+	end := resolution.Truncate(now.Add(-settle))
+
+	var from time.Time
+	latest, err := s.Repository.SelectRollupLatest(ctx, id, resolution)
+	if err != nil {
+		return 0, err
+	}
+	if latest != nil {
+		from = resolution.Next(latest.Time())
+	}
+
+	stored := 0
+	for from.Before(end) {
+		if err := ctx.Err(); err != nil {
+			return stored, err
+		}
+
+		// Skip to the bucket of the next measurement, so gaps are not scanned.
+		origin, err := s.Repository.SelectRollupOrigin(ctx, id, internal.NewTimeValue(from))
+		if err != nil {
+			return stored, err
+		}
+		if origin == nil {
+			break
+		}
+		from = resolution.Truncate(origin.Time())
+		if !from.Before(end) {
+			break
+		}
+
+		to := from
+		for to.Before(end) && to.Sub(from) < window {
+			to = resolution.Next(to)
+		}
+
+		samples, err := s.Repository.SelectRollupSample(ctx, id, internal.NewTimeValue(from), internal.NewTimeValue(to))
+		if err != nil {
+			return stored, err
+		}
+		rollups := Aggregate(id, resolution, samples)
+		if err := s.Repository.UpsertRollup(ctx, rollups); err != nil {
+			return stored, err
+		}
+		stored += len(rollups)
+		from = to
+	}
+
+	return stored, nil
+}
+
+// combine will combine the hourly rollups in the complete daily buckets of the monitor,
+// and return the number of rollups stored.
+//
+// Measurements may be deleted by retention once they are in an hourly rollup,
+// so daily rollups are never built from measurements.
+func (s RollupService) combine(ctx context.Context, id int, now time.Time) (int, error) {
+	end := internal.NewTimeValue(Day.Truncate(now.Add(-settle)))
+
+	params := SelectRollupParams{Before: &end}
+	latest, err := s.Repository.SelectRollupLatest(ctx, id, Day)
+	if err != nil {
+		return 0, err
+	}
+	if latest != nil {
+		from := internal.NewTimeValue(Day.Next(latest.Time()))
+		params.After = &from
+	}
+
+	hourly, err := s.Repository.SelectRollup(ctx, id, Hour, &params)
+	if err != nil || len(hourly) == 0 {
+		return 0, err
+	}
+	rollups := Combine(id, Day, hourly)
+	if err := s.Repository.UpsertRollup(ctx, rollups); err != nil {
+		return 0, err
+	}
+	return len(rollups), nil
+}
+
+// GetRollups returns the rollups of the monitor at the resolution, ordered by bucket.
+func (s RollupService) GetRollups(ctx context.Context, id int, resolution Resolution, params *SelectRollupParams) ([]Rollup, error) {
+	rollups, err := s.Repository.SelectRollup(ctx, id, resolution, params)
+	if rollups == nil {
+		rollups = []Rollup{}
+	}
+	return rollups, err
+}
@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/jmkng/zenin/internal"
@@ -11,14 +12,22 @@ const (
 	DefaultOpenDelimiter  = "{{"
 	DefaultCloseDelimiter = "}}"
 
-	DelimitersKey = "delimiters"
-	ThemeKey      = "theme"
+	DelimitersKey    = "delimiters"
+	ThemeKey         = "theme"
+	RetentionDaysKey = "retention_days"
+	RetentionRowsKey = "retention_rows"
 )
 
 // Settings is the settings domain type.
 type Settings struct {
 	Theme      *string              `json:"theme"`
 	Delimiters *internal.ArrayValue `json:"delimiters"`
+	// RetentionDays is the number of days measurements are kept.
+	// Measurements are kept forever when nil, unless a monitor overrides it.
+	RetentionDays *int `json:"retentionDays"`
+	// RetentionRows is the number of measurements kept for each monitor.
+	// All measurements are kept when nil, unless a monitor overrides it.
+	RetentionRows *int `json:"retentionRows"`
 }
 
 func (m Settings) Validate() error {
@@ -30,12 +39,28 @@ func (m Settings) Validate() error {
 		errors = append(errors, "value for field `delimiters` must be an array of two strings")
 	}
 
+	if m.RetentionDays != nil && *m.RetentionDays <= 0 {
+		errors = append(errors, "value for field `retentionDays` must be greater than zero")
+	}
+	if m.RetentionRows != nil && *m.RetentionRows <= 0 {
+		errors = append(errors, "value for field `retentionRows` must be greater than zero")
+	}
+
 	if len(errors) > 0 {
 		return env.NewValidation(errors...)
 	}
 	return nil
 }
 
+// IntText returns the value formatted for storage in the text column, or nil.
+func IntText(v *int) *string {
+	if v == nil {
+		return nil
+	}
+	text := strconv.Itoa(*v)
+	return &text
+}
+
 // TextValue is a string value container for the settings domain type.
 type TextValue struct {
 	SettingsFields
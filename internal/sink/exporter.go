@@ -0,0 +1,298 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/metrics"
+)
+
+const (
+	// DefaultCapacity is the number of records buffered by an `Exporter` when not configured.
+	DefaultCapacity = 4096
+	// DefaultBatchSize is the maximum number of records sent at once when not configured.
+	DefaultBatchSize = 500
+	// DefaultFlushInterval is the longest time a record waits for a batch to fill when not configured.
+	DefaultFlushInterval = 5 * time.Second
+	// DefaultRetries is the number of times a failed batch is sent again when not configured.
+	DefaultRetries = 5
+	// DefaultRetryWait is the time waited before the first retry when not configured.
+	// The wait doubles after each retry, up to `maxRetryWait`.
+	DefaultRetryWait = time.Second
+	// DefaultTimeout is the time allowed for one attempt to send a batch when not configured.
+	DefaultTimeout = 10 * time.Second
+
+	maxRetryWait = time.Minute
+)
+
+// Options configure an `Exporter`. Defaults are used for zero values.
+type Options struct {
+	Capacity      int
+	BatchSize     int
+	FlushInterval time.Duration
+	Retries       int
+	RetryWait     time.Duration
+	Timeout       time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Capacity <= 0 {
+		o.Capacity = DefaultCapacity
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = DefaultFlushInterval
+	}
+	if o.Retries <= 0 {
+		o.Retries = DefaultRetries
+	}
+	if o.RetryWait <= 0 {
+		o.RetryWait = DefaultRetryWait
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	return o
+}
+
+// NewExporter returns a new `Exporter` for the sink, with its goroutine started.
+func NewExporter(s Sink, o Options) *Exporter {
+	o = o.withDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Exporter{
+		sink:    s,
+		options: o,
+		queue:   make(chan Record, o.Capacity),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go e.run()
+	return e
+}
+
+// Exporter delivers records to a `Sink` in batches from its own goroutine,
+// so a slow sink never blocks the caller.
+// Safe for concurrent use.
+//
+// Records are dropped when the buffer is full, and batches are dropped once
+// they have failed more than the configured number of retries.
+type Exporter struct {
+	sink    Sink
+	options Options
+	queue   chan Record
+	// Closed once the goroutine has stopped.
+	done chan struct{}
+	// Cancelled to abandon the batch being sent.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex  sync.RWMutex
+	closed bool
+
+	sent    atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// ExporterStats is a snapshot of an `Exporter`.
+type ExporterStats struct {
+	Sink     string `json:"sink"`
+	Capacity int    `json:"capacity"`
+	Queued   int    `json:"queued"`
+	// Sent is the number of records delivered.
+	Sent uint64 `json:"sent"`
+	// Dropped is the number of records dropped because the buffer was full.
+	Dropped uint64 `json:"dropped"`
+	// Failed is the number of records dropped because they could not be delivered.
+	Failed uint64 `json:"failed"`
+}
+
+// Offer will queue the record, and return false if it was dropped
+// because the buffer is full or the `Exporter` is closed.
+func (e *Exporter) Offer(r Record) bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if e.closed {
+		return false
+	}
+
+	select {
+	case e.queue <- r:
+		return true
+	default:
+		e.dropped.Add(1)
+		metrics.Default.Inc(metrics.SinkRecords, "sink", e.sink.Name(), "result", "dropped")
+		env.Debug("sink dropped record, buffer is full", "sink", e.sink.Name())
+		return false
+	}
+}
+
+// Name returns the name of the sink.
+func (e *Exporter) Name() string {
+	return e.sink.Name()
+}
+
+// Stats returns a snapshot of the `Exporter`.
+func (e *Exporter) Stats() ExporterStats {
+	return ExporterStats{
+		Sink:     e.sink.Name(),
+		Capacity: cap(e.queue),
+		Queued:   len(e.queue),
+		Sent:     e.sent.Load(),
+		Dropped:  e.dropped.Load(),
+		Failed:   e.failed.Load(),
+	}
+}
+
+// Close will stop accepting records, and block until the buffered records are sent.
+//
+// When the context is done first, the batch being sent is abandoned and the
+// remaining records are dropped.
+func (e *Exporter) Close(ctx context.Context) error {
+	e.mutex.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+	e.mutex.Unlock()
+
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		e.cancel()
+		<-e.done
+		return ctx.Err()
+	}
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+	defer e.cancel()
+
+	ticker := time.NewTicker(e.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, e.options.BatchSize)
+	for {
+		select {
+		case r, ok := <-e.queue:
+			if !ok {
+				e.flush(batch)
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) < e.options.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+		e.flush(batch)
+		batch = make([]Record, 0, e.options.BatchSize)
+	}
+}
+
+// flush will send the batch, retrying with an increasing wait until it is sent
+// or the retries are exhausted.
+func (e *Exporter) flush(batch []Record) {
+	if len(batch) == 0 {
+		return
+	}
+	name := e.sink.Name()
+
+	wait := e.options.RetryWait
+	for attempt := 0; ; attempt++ {
+		if e.ctx.Err() != nil {
+			e.fail(batch, e.ctx.Err())
+			return
+		}
+		ctx, cancel := context.WithTimeout(e.ctx, e.options.Timeout)
+		err := e.sink.Send(ctx, batch)
+		cancel()
+		if err == nil {
+			e.sent.Add(uint64(len(batch)))
+			metrics.Default.Add(metrics.SinkRecords, float64(len(batch)), "sink", name, "result", "sent")
+			return
+		}
+		if IsPermanent(err) || attempt >= e.options.Retries {
+			e.fail(batch, err)
+			return
+		}
+
+		env.Warn("sink failed to send records, retrying", "sink", name, "records", len(batch),
+			"attempt", attempt+1, "wait", wait, "error", err)
+		select {
+		case <-time.After(wait):
+		case <-e.ctx.Done():
+		}
+		wait = min(wait*2, maxRetryWait)
+	}
+}
+
+func (e *Exporter) fail(batch []Record, err error) {
+	e.failed.Add(uint64(len(batch)))
+	metrics.Default.Add(metrics.SinkRecords, float64(len(batch)), "sink", e.sink.Name(), "result", "failed")
+	env.Error("sink dropped records after failing to send them", "sink", e.sink.Name(), "records", len(batch), "error", err)
+}
+
+// Exporters is a set of `Exporter` that receive the same records.
+type Exporters []*Exporter
+
+// Offer will queue the record on every `Exporter`.
+func (e Exporters) Offer(r Record) {
+	for _, v := range e {
+		v.Offer(r)
+	}
+}
+
+// Close will close every `Exporter` at once, sharing the context.
+func (e Exporters) Close(ctx context.Context) error {
+	errs := make([]error, len(e))
+	var group sync.WaitGroup
+	for i, v := range e {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			errs[i] = v.Close(ctx)
+		}()
+	}
+	group.Wait()
+	return errors.Join(errs...)
+}
+
+// NewExporters returns an `Exporter` for each sink configured in the environment.
+func NewExporters(e env.Environment) (Exporters, error) {
+	options := Options{Capacity: int(e.SinkBuffer)}
+	exporters := Exporters{}
+
+	if e.SinkInfluxURL != "" {
+		s, err := NewInfluxSink(e.SinkInfluxURL, e.SinkInfluxToken)
+		if err != nil {
+			return nil, fmt.Errorf("influx sink is invalid: %w", err)
+		}
+		exporters = append(exporters, NewExporter(s, options))
+	}
+	if e.SinkOTLPURL != "" {
+		s, err := NewOTLPSink(e.SinkOTLPURL, e.SinkOTLPHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("otlp sink is invalid: %w", err)
+		}
+		exporters = append(exporters, NewExporter(s, options))
+	}
+	if e.SinkStatsDAddress != "" {
+		s, err := NewStatsDSink(e.SinkStatsDAddress)
+		if err != nil {
+			return nil, fmt.Errorf("statsd sink is invalid: %w", err)
+		}
+		exporters = append(exporters, NewExporter(s, options))
+	}
+	return exporters, nil
+}
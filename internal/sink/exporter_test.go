@@ -0,0 +1,157 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// testSink records the batches it receives, and fails according to `fail`.
+type testSink struct {
+	mutex   sync.Mutex
+	batches [][]Record
+	calls   int
+	// fail returns the error for a call, starting at 1.
+	fail func(call int) error
+	// block is received from before each call returns, when not nil.
+	block chan struct{}
+}
+
+func (t *testSink) Name() string {
+	return "test"
+}
+
+func (t *testSink) Send(ctx context.Context, records []Record) error {
+	if t.block != nil {
+		select {
+		case <-t.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.calls++
+	if t.fail != nil {
+		if err := t.fail(t.calls); err != nil {
+			return err
+		}
+	}
+	t.batches = append(t.batches, records)
+	return nil
+}
+
+func (t *testSink) received() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	count := 0
+	for _, v := range t.batches {
+		count += len(v)
+	}
+	return count
+}
+
+func newTestRecord(id int) Record {
+	return Record{MonitorName: "test", Measurement: measurement.Measurement{MonitorId: &id, Span: measurement.NewSpan()}}
+}
+
+func TestExporterBatch(t *testing.T) {
+	s := &testSink{}
+	e := NewExporter(s, Options{BatchSize: 2, FlushInterval: time.Hour})
+	for i := range 5 {
+		debug.Assert(t, e.Offer(newTestRecord(i)), "expected record to be queued")
+	}
+	err := e.Close(context.Background())
+	debug.Assert(t, err == nil, "expected close to finish")
+
+	// Two full batches, and the remainder when closed.
+	debug.AssertEqual(t, len(s.batches), 3)
+	debug.AssertEqual(t, s.received(), 5)
+	debug.AssertEqual(t, e.Stats().Sent, uint64(5))
+	debug.Assert(t, !e.Offer(newTestRecord(6)), "expected closed exporter to refuse records")
+}
+
+func TestExporterFlushInterval(t *testing.T) {
+	s := &testSink{}
+	e := NewExporter(s, Options{FlushInterval: 10 * time.Millisecond})
+	defer e.Close(context.Background())
+	e.Offer(newTestRecord(1))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.received() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	debug.AssertEqual(t, s.received(), 1)
+}
+
+func TestExporterBounded(t *testing.T) {
+	// The sink blocks, so the buffer fills while the first batch is being sent.
+	s := &testSink{block: make(chan struct{})}
+	e := NewExporter(s, Options{Capacity: 2, BatchSize: 1, FlushInterval: time.Hour})
+
+	e.Offer(newTestRecord(0))
+	deadline := time.Now().Add(2 * time.Second)
+	for e.Stats().Queued > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	debug.Assert(t, e.Offer(newTestRecord(1)), "expected record to be queued")
+	debug.Assert(t, e.Offer(newTestRecord(2)), "expected record to be queued")
+	debug.Assert(t, !e.Offer(newTestRecord(3)), "expected record to be dropped")
+	debug.AssertEqual(t, e.Stats().Dropped, uint64(1))
+
+	close(s.block)
+	e.Close(context.Background())
+	debug.AssertEqual(t, s.received(), 3)
+}
+
+func TestExporterRetry(t *testing.T) {
+	transient := errors.New("unavailable")
+	cases := []struct {
+		fail   func(call int) error
+		calls  int
+		sent   uint64
+		failed uint64
+	}{
+		// Succeeds on the third attempt.
+		{func(call int) error {
+			if call < 3 {
+				return transient
+			}
+			return nil
+		}, 3, 1, 0},
+		// Retries are exhausted.
+		{func(int) error { return transient }, 3, 0, 1},
+		// Permanent errors are not retried.
+		{func(int) error { return Permanent(transient) }, 1, 0, 1},
+	}
+
+	for _, v := range cases {
+		s := &testSink{fail: v.fail}
+		e := NewExporter(s, Options{BatchSize: 1, Retries: 2, RetryWait: time.Millisecond})
+		e.Offer(newTestRecord(1))
+		e.Close(context.Background())
+
+		debug.AssertEqual(t, s.calls, v.calls)
+		debug.AssertEqual(t, e.Stats().Sent, v.sent)
+		debug.AssertEqual(t, e.Stats().Failed, v.failed)
+	}
+}
+
+func TestExporterCloseDeadline(t *testing.T) {
+	// The sink never finishes, so closing gives up at the deadline.
+	s := &testSink{block: make(chan struct{})}
+	e := NewExporter(s, Options{BatchSize: 1})
+	e.Offer(newTestRecord(1))
+	e.Offer(newTestRecord(2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := Exporters{e}.Close(ctx)
+	debug.Assert(t, errors.Is(err, context.DeadlineExceeded), "expected close to time out")
+	debug.AssertEqual(t, e.Stats().Failed, uint64(2))
+}
@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// responseLimit is the maximum number of bytes of an error response included in an error.
+const responseLimit = 512
+
+// parseEndpoint returns the URL if it is an absolute `http` or `https` URL.
+func parseEndpoint(address string) (*url.URL, error) {
+	endpoint, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, fmt.Errorf("url `%v` must be an absolute http or https url", address)
+	}
+	return endpoint, nil
+}
+
+// post will send the body to the endpoint.
+//
+// Responses other than 408, 429 and 5xx are not retried.
+func post(ctx context.Context, client *http.Client, endpoint string, header http.Header, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	for k, v := range header {
+		request.Header[k] = v
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		io.Copy(io.Discard, response.Body)
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(response.Body, responseLimit))
+	err = fmt.Errorf("unexpected response status %v: %s", response.StatusCode, bytes.TrimSpace(message))
+	switch c := response.StatusCode; {
+	case c == http.StatusRequestTimeout, c == http.StatusTooManyRequests, c >= 500:
+		return err
+	}
+	return Permanent(err)
+}
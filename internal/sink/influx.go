@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// influxMeasurement is the InfluxDB measurement name of every line.
+const influxMeasurement = "zenin_measurement"
+
+// NewInfluxSink returns a new `InfluxSink` for the URL of a write endpoint, such as
+// `http://localhost:8086/api/v2/write?org=zenin&bucket=zenin`.
+//
+// The token is sent in the authorization header when it is not empty.
+func NewInfluxSink(address string, token env.Secret) (InfluxSink, error) {
+	endpoint, err := parseEndpoint(address)
+	if err != nil {
+		return InfluxSink{}, err
+	}
+	// Timestamps are written in nanoseconds, which is also the default precision.
+	query := endpoint.Query()
+	query.Set("precision", "ns")
+	endpoint.RawQuery = query.Encode()
+
+	return InfluxSink{endpoint: endpoint.String(), token: token, client: &http.Client{}}, nil
+}
+
+// InfluxSink writes records to InfluxDB using the line protocol over HTTP.
+//
+// Each record is a line of the `zenin_measurement` measurement, tagged with
+// the monitor, kind and state, with a field for each `Value`.
+type InfluxSink struct {
+	endpoint string
+	token    env.Secret
+	client   *http.Client
+}
+
+// Name implements `Sink.Name` for `InfluxSink`.
+func (i InfluxSink) Name() string {
+	return "influx"
+}
+
+// Send implements `Sink.Send` for `InfluxSink`.
+func (i InfluxSink) Send(ctx context.Context, records []Record) error {
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	if len(i.token) > 0 {
+		header.Set("Authorization", "Token "+string(i.token))
+	}
+	return post(ctx, i.client, i.endpoint, header, []byte(FormatLines(records)))
+}
+
+// FormatLines returns the records in the InfluxDB line protocol.
+func FormatLines(records []Record) string {
+	var b strings.Builder
+	for _, r := range records {
+		b.WriteString(influxMeasurement)
+		for _, v := range r.Tags() {
+			b.WriteString("," + escapeInflux(v[0]) + "=" + escapeInflux(v[1]))
+		}
+		for i, v := range Values(r.Measurement) {
+			if i == 0 {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte(',')
+			}
+			b.WriteString(escapeInflux(v.Name) + "=")
+			if v.Integer {
+				b.WriteString(strconv.FormatInt(int64(v.Value), 10) + "i")
+			} else {
+				b.WriteString(strconv.FormatFloat(v.Value, 'f', -1, 64))
+			}
+		}
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(r.Measurement.CreatedAt.Time().UnixNano(), 10))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// influxReplacer escapes the characters that are significant in tag keys, tag values and field keys.
+var influxReplacer = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+
+func escapeInflux(value string) string {
+	return influxReplacer.Replace(value)
+}
@@ -0,0 +1,159 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// otlpPrefix is prepended to the name of every OTLP metric.
+const otlpPrefix = "zenin.monitor."
+
+// NewOTLPSink returns a new `OTLPSink` for the URL of an OTLP/HTTP metrics endpoint,
+// such as `http://localhost:4318/v1/metrics`.
+//
+// The headers are sent with every request, and are given as comma separated `key=value` pairs.
+func NewOTLPSink(address string, headers env.Secret) (OTLPSink, error) {
+	endpoint, err := parseEndpoint(address)
+	if err != nil {
+		return OTLPSink{}, err
+	}
+
+	header := http.Header{}
+	for _, v := range strings.Split(string(headers), ",") {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return OTLPSink{}, fmt.Errorf("header `%v` must be in the form key=value", strings.TrimSpace(key))
+		}
+		header.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+	header.Set("Content-Type", "application/json")
+
+	return OTLPSink{endpoint: endpoint.String(), header: header, client: &http.Client{}}, nil
+}
+
+// OTLPSink writes records to an OpenTelemetry collector as gauges, using OTLP/HTTP
+// with the JSON encoding.
+//
+// Each `Value` is a metric named with the `zenin.monitor.` prefix, and each record is
+// a data point with attributes for the monitor, kind and state.
+type OTLPSink struct {
+	endpoint string
+	header   http.Header
+	client   *http.Client
+}
+
+// Name implements `Sink.Name` for `OTLPSink`.
+func (o OTLPSink) Name() string {
+	return "otlp"
+}
+
+// Send implements `Sink.Send` for `OTLPSink`.
+func (o OTLPSink) Send(ctx context.Context, records []Record) error {
+	body, err := json.Marshal(NewOTLPRequest(records))
+	if err != nil {
+		return Permanent(err)
+	}
+	return post(ctx, o.client, o.endpoint, o.header, body)
+}
+
+// OTLPRequest is an `ExportMetricsServiceRequest` in the OTLP JSON encoding.
+type OTLPRequest struct {
+	ResourceMetrics []OTLPResourceMetrics `json:"resourceMetrics"`
+}
+
+type OTLPResourceMetrics struct {
+	Resource     OTLPResource       `json:"resource"`
+	ScopeMetrics []OTLPScopeMetrics `json:"scopeMetrics"`
+}
+
+type OTLPResource struct {
+	Attributes []OTLPAttribute `json:"attributes"`
+}
+
+type OTLPScopeMetrics struct {
+	Scope   OTLPScope    `json:"scope"`
+	Metrics []OTLPMetric `json:"metrics"`
+}
+
+type OTLPScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type OTLPMetric struct {
+	Name  string    `json:"name"`
+	Unit  string    `json:"unit,omitempty"`
+	Gauge OTLPGauge `json:"gauge"`
+}
+
+type OTLPGauge struct {
+	DataPoints []OTLPDataPoint `json:"dataPoints"`
+}
+
+// OTLPDataPoint is a `NumberDataPoint`. Integers and timestamps are encoded as strings.
+type OTLPDataPoint struct {
+	Attributes   []OTLPAttribute `json:"attributes"`
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsDouble     *float64        `json:"asDouble,omitempty"`
+	AsInt        *string         `json:"asInt,omitempty"`
+}
+
+type OTLPAttribute struct {
+	Key   string             `json:"key"`
+	Value OTLPAttributeValue `json:"value"`
+}
+
+type OTLPAttributeValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// NewOTLPRequest returns an `OTLPRequest` with a gauge for each value of the records.
+func NewOTLPRequest(records []Record) OTLPRequest {
+	metrics := []OTLPMetric{}
+	index := map[string]int{}
+	for _, r := range records {
+		attributes := []OTLPAttribute{}
+		for _, v := range r.Tags() {
+			attributes = append(attributes, OTLPAttribute{Key: v[0], Value: OTLPAttributeValue{StringValue: v[1]}})
+		}
+		timestamp := strconv.FormatInt(r.Measurement.CreatedAt.Time().UnixNano(), 10)
+
+		for _, v := range Values(r.Measurement) {
+			point := OTLPDataPoint{Attributes: attributes, TimeUnixNano: timestamp}
+			if v.Integer {
+				value := strconv.FormatInt(int64(v.Value), 10)
+				point.AsInt = &value
+			} else {
+				value := v.Value
+				point.AsDouble = &value
+			}
+
+			i, ok := index[v.Name]
+			if !ok {
+				i = len(metrics)
+				index[v.Name] = i
+				metrics = append(metrics, OTLPMetric{Name: otlpPrefix + v.Name, Unit: v.Unit})
+			}
+			metrics[i].Gauge.DataPoints = append(metrics[i].Gauge.DataPoints, point)
+		}
+	}
+
+	return OTLPRequest{ResourceMetrics: []OTLPResourceMetrics{{
+		Resource: OTLPResource{Attributes: []OTLPAttribute{
+			{Key: "service.name", Value: OTLPAttributeValue{StringValue: "zenin"}},
+		}},
+		ScopeMetrics: []OTLPScopeMetrics{{
+			Scope:   OTLPScope{Name: "github.com/jmkng/zenin", Version: env.Version},
+			Metrics: metrics,
+		}},
+	}}}
+}
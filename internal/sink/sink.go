@@ -0,0 +1,117 @@
+// Package sink exports measurements to external time series databases.
+package sink
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// Record is a measurement delivered to a `Sink`.
+type Record struct {
+	MonitorName string
+	Measurement measurement.Measurement
+}
+
+// Tags returns the identifying labels of the record, in a stable order.
+func (r Record) Tags() [][2]string {
+	tags := [][2]string{}
+	if r.Measurement.MonitorId != nil {
+		tags = append(tags, [2]string{"monitor_id", strconv.Itoa(*r.Measurement.MonitorId)})
+	}
+	if r.MonitorName != "" {
+		tags = append(tags, [2]string{"monitor_name", r.MonitorName})
+	}
+	tags = append(tags, [2]string{"kind", string(r.Measurement.Kind)}, [2]string{"state", string(r.Measurement.State)})
+	return tags
+}
+
+// Sink is a destination for measurements outside of the repository.
+type Sink interface {
+	// Name returns a short name for the sink, used in logs and metrics.
+	Name() string
+	// Send will deliver the records.
+	//
+	// The records are sent again after an error, unless it is wrapped by `Permanent`.
+	Send(ctx context.Context, records []Record) error
+}
+
+// Value is a numeric value of a measurement.
+type Value struct {
+	Name  string
+	Value float64
+	// Integer is true if the value is always a whole number.
+	Integer bool
+	// Unit is the unit of the value in UCUM notation, or empty when the value has no unit.
+	Unit string
+}
+
+// StateCodes maps each state to the number exported as the `state` value.
+var StateCodes = map[measurement.ProbeState]int{
+	measurement.Ok:          0,
+	measurement.Warn:        1,
+	measurement.Dead:        2,
+	measurement.Unreachable: 3,
+}
+
+// Values returns the numeric values of the measurement. Values that were not recorded are omitted.
+func Values(m measurement.Measurement) []Value {
+	values := []Value{
+		{Name: "state", Value: float64(StateCodes[m.State]), Integer: true},
+		{Name: "duration", Value: m.Duration, Unit: "ms"},
+	}
+	flag := func(name string, value bool) {
+		v := 0.0
+		if value {
+			v = 1
+		}
+		values = append(values, Value{Name: name, Value: v, Integer: true})
+	}
+	flag("flapping", m.Flapping)
+	flag("maintenance", m.Maintenance)
+
+	integer := func(name string, value *int) {
+		if value != nil {
+			values = append(values, Value{Name: name, Value: float64(*value), Integer: true})
+		}
+	}
+	float := func(name string, value *float64, unit string) {
+		if value != nil {
+			values = append(values, Value{Name: name, Value: *value, Unit: unit})
+		}
+	}
+	integer("http_status_code", m.HTTPStatusCode)
+	integer("icmp_packets_in", m.ICMPPacketsIn)
+	integer("icmp_packets_out", m.ICMPPacketsOut)
+	float("icmp_avg_rtt", m.ICMPAvgRTT, "ms")
+	float("dns_rtt", m.DNSRTT, "ms")
+	float("metric_value", m.MetricValue, "")
+	integer("plugin_exit_code", m.PluginExitCode)
+	return values
+}
+
+// permanentError is an error that is not resolved by sending the records again.
+type permanentError struct {
+	err error
+}
+
+func (p permanentError) Error() string {
+	return p.err.Error()
+}
+
+func (p permanentError) Unwrap() error {
+	return p.err
+}
+
+// Permanent returns an error that prevents the records from being sent again.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent returns true if the error was wrapped by `Permanent`.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
@@ -0,0 +1,176 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+// newSinkRecord returns a record of an HTTP measurement.
+func newSinkRecord() Record {
+	id := 7
+	code := 503
+	m := measurement.Measurement{
+		MonitorId: &id,
+		CreatedAt: internal.NewTimeValue(time.Unix(1700000000, 500)),
+		Duration:  12.5,
+		Span:      measurement.NewSpan(),
+	}
+	m.Kind = measurement.HTTP
+	m.State = measurement.Dead
+	m.HTTPStatusCode = &code
+	return Record{MonitorName: "api, east", Measurement: m}
+}
+
+func TestFormatLines(t *testing.T) {
+	lines := FormatLines([]Record{newSinkRecord()})
+	debug.AssertEqual(t, lines, `zenin_measurement,monitor_id=7,monitor_name=api\,\ east,kind=HTTP,state=DEAD `+
+		"state=2i,duration=12.5,flapping=0i,maintenance=0i,http_status_code=503i 1700000000000000500\n")
+}
+
+func TestInfluxSink(t *testing.T) {
+	var body, query, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		query = r.URL.RawQuery
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s, err := NewInfluxSink(server.URL+"/api/v2/write?org=zenin&bucket=zenin", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Send(context.Background(), []Record{newSinkRecord(), newSinkRecord()})
+	debug.Assert(t, err == nil, "expected send to succeed")
+	debug.AssertEqual(t, strings.Count(body, "\n"), 2)
+	debug.AssertEqual(t, query, "bucket=zenin&org=zenin&precision=ns")
+	debug.AssertEqual(t, authorization, "Token secret")
+
+	_, err = NewInfluxSink("localhost:8086/write", nil)
+	debug.Assert(t, err != nil, "expected error for url without scheme")
+}
+
+func TestHTTPSinkStatus(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	s, _ := NewInfluxSink(server.URL, nil)
+
+	cases := []struct {
+		status    int
+		err       bool
+		permanent bool
+	}{
+		{http.StatusNoContent, false, false},
+		{http.StatusBadRequest, true, true},
+		{http.StatusUnauthorized, true, true},
+		{http.StatusTooManyRequests, true, false},
+		{http.StatusServiceUnavailable, true, false},
+	}
+	for _, v := range cases {
+		status = v.status
+		err := s.Send(context.Background(), []Record{newSinkRecord()})
+		debug.AssertEqual(t, err != nil, v.err)
+		debug.AssertEqual(t, IsPermanent(err), v.permanent)
+	}
+}
+
+func TestOTLPSink(t *testing.T) {
+	var request OTLPRequest
+	var contentType, header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		header = r.Header.Get("X-Api-Key")
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	s, err := NewOTLPSink(server.URL+"/v1/metrics", []byte("x-api-key=secret, other = value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Send(context.Background(), []Record{newSinkRecord(), newSinkRecord()})
+	debug.Assert(t, err == nil, "expected send to succeed")
+	debug.AssertEqual(t, contentType, "application/json")
+	debug.AssertEqual(t, header, "secret")
+
+	debug.AssertEqual(t, len(request.ResourceMetrics), 1)
+	metrics := request.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	debug.AssertEqual(t, len(metrics), 5)
+
+	duration := metrics[1]
+	debug.AssertEqual(t, duration.Name, "zenin.monitor.duration")
+	debug.AssertEqual(t, duration.Unit, "ms")
+	debug.AssertEqual(t, len(duration.Gauge.DataPoints), 2)
+	point := duration.Gauge.DataPoints[0]
+	debug.AssertEqual(t, *point.AsDouble, 12.5)
+	debug.AssertEqual(t, point.TimeUnixNano, "1700000000000000500")
+	debug.AssertEqual(t, point.Attributes[1].Value.StringValue, "api, east")
+
+	code := metrics[4]
+	debug.AssertEqual(t, code.Name, "zenin.monitor.http_status_code")
+	debug.AssertEqual(t, *code.Gauge.DataPoints[0].AsInt, "503")
+
+	_, err = NewOTLPSink(server.URL, []byte("invalid"))
+	debug.Assert(t, err != nil, "expected error for header without value")
+}
+
+func TestStatsDSink(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	s, err := NewStatsDSink(server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Send(context.Background(), []Record{newSinkRecord()})
+	debug.Assert(t, err == nil, "expected send to succeed")
+
+	server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buffer := make([]byte, maxDatagramSize)
+	n, _, err := server.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(buffer[:n]), "\n")
+	debug.AssertEqual(t, len(lines), 5)
+	debug.AssertEqual(t, lines[1], "zenin.monitor.duration:12.5|ms|#monitor_id:7,monitor_name:api_ east,kind:HTTP,state:DEAD")
+	debug.AssertEqual(t, lines[4], "zenin.monitor.http_status_code:503|g|#monitor_id:7,monitor_name:api_ east,kind:HTTP,state:DEAD")
+
+	_, err = NewStatsDSink("localhost")
+	debug.Assert(t, err != nil, "expected error for address without port")
+}
+
+func TestFormatDatagrams(t *testing.T) {
+	records := []Record{}
+	for range 100 {
+		records = append(records, newSinkRecord())
+	}
+	datagrams := FormatDatagrams(records)
+	debug.Assert(t, len(datagrams) > 1, "expected records to be split across datagrams")
+	lines := 0
+	for _, v := range datagrams {
+		debug.Assert(t, len(v) <= maxDatagramSize, "expected datagram to fit the size limit")
+		lines += strings.Count(string(v), "\n") + 1
+	}
+	debug.AssertEqual(t, lines, 500)
+}
@@ -0,0 +1,99 @@
+package sink
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// statsdPrefix is prepended to the name of every StatsD metric.
+	statsdPrefix = "zenin.monitor."
+	// maxDatagramSize is the largest datagram written by `StatsDSink`,
+	// small enough to avoid fragmentation on common networks.
+	maxDatagramSize = 1432
+)
+
+// NewStatsDSink returns a new `StatsDSink` for the address of a StatsD server, such as `localhost:8125`.
+func NewStatsDSink(address string) (StatsDSink, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return StatsDSink{}, err
+	}
+	return StatsDSink{address: address}, nil
+}
+
+// StatsDSink writes records to a StatsD server over UDP.
+//
+// The duration is written as a timer and the other values as gauges, named with the
+// `zenin.monitor.` prefix. The monitor, kind and state are written as DogStatsD tags,
+// which are supported by most servers.
+type StatsDSink struct {
+	address string
+}
+
+// Name implements `Sink.Name` for `StatsDSink`.
+func (s StatsDSink) Name() string {
+	return "statsd"
+}
+
+// Send implements `Sink.Send` for `StatsDSink`.
+func (s StatsDSink) Send(ctx context.Context, records []Record) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", s.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+	}
+
+	for _, v := range FormatDatagrams(records) {
+		if _, err := conn.Write(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatDatagrams returns the records in the StatsD format, with lines packed
+// into datagrams of up to `maxDatagramSize` bytes.
+func FormatDatagrams(records []Record) [][]byte {
+	datagrams := [][]byte{}
+	var current []byte
+	for _, r := range records {
+		tags := []string{}
+		for _, v := range r.Tags() {
+			tags = append(tags, sanitizeStatsD(v[0])+":"+sanitizeStatsD(v[1]))
+		}
+		suffix := "|#" + strings.Join(tags, ",")
+
+		for _, v := range Values(r.Measurement) {
+			kind := "|g"
+			if v.Name == "duration" {
+				kind = "|ms"
+			}
+			line := statsdPrefix + v.Name + ":" + strconv.FormatFloat(v.Value, 'f', -1, 64) + kind + suffix
+			if len(current) > 0 && len(current)+1+len(line) > maxDatagramSize {
+				datagrams = append(datagrams, current)
+				current = nil
+			}
+			if len(current) > 0 {
+				current = append(current, '\n')
+			}
+			current = append(current, line...)
+		}
+	}
+	if len(current) > 0 {
+		datagrams = append(datagrams, current)
+	}
+	return datagrams
+}
+
+// statsdReplacer replaces the characters that are significant in DogStatsD tags.
+var statsdReplacer = strings.NewReplacer(",", "_", "|", "_", ":", "_", "#", "_", "\n", " ")
+
+func sanitizeStatsD(value string) string {
+	return statsdReplacer.Replace(value)
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/rollup"
+)
+
+// gapFactor is multiplied by the interval of a monitor to find the longest time
+// between measurements that is counted as uptime or downtime.
+const gapFactor = 3
+
+// MaintenanceRollupError means that measurements taken during maintenance were requested
+// for a range that is answered from rollups, which only count them.
+var MaintenanceRollupError env.Validation = env.NewValidation("Measurements taken during maintenance can't be included in a range that has been rolled up.")
+
+// NewStatsService returns a new `StatsService`.
+func NewStatsService(r rollup.RollupRepository, m monitor.MonitorRepository) StatsService {
+	return StatsService{Repository: r, Monitor: m}
+}
+
+// StatsService is a service used to compute availability statistics.
+type StatsService struct {
+	Repository rollup.RollupRepository
+	Monitor    monitor.MonitorRepository
+}
+
+// GetStats returns the `Stats` of the monitor from `from` until `to`, or until now
+// if `to` is in the future. Returns false if the monitor does not exist.
+//
+// Statistics are computed from measurements, unless they were pruned from the start
+// of the range and hourly rollups reach further back.
+// Measurements taken during maintenance are counted when `maintenance` is true,
+// which is not supported by rollups and returns `MaintenanceRollupError`.
+func (s StatsService) GetStats(ctx context.Context, id int, from time.Time, to time.Time, maintenance bool) (Stats, bool, error) {
+	found, err := s.Monitor.SelectMonitor(ctx, 0, &monitor.SelectMonitorParams{Id: &[]int{id}})
+	if err != nil || len(found) == 0 {
+		return Stats{}, false, err
+	}
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+
+	origin, err := s.Repository.SelectRollupOrigin(ctx, id, internal.NewTimeValue(from))
+	if err != nil {
+		return Stats{}, true, err
+	}
+	if origin == nil || origin.Time().After(from) {
+		after, before := internal.NewTimeValue(from), internal.NewTimeValue(to)
+		rollups, err := s.Repository.SelectRollup(ctx, id, rollup.Hour,
+			&rollup.SelectRollupParams{After: &after, Before: &before})
+		if err != nil {
+			return Stats{}, true, err
+		}
+		if len(rollups) > 0 && (origin == nil || rollups[0].Bucket.Time().Before(rollup.Hour.Truncate(origin.Time()))) {
+			if maintenance {
+				return Stats{}, true, MaintenanceRollupError
+			}
+			// Measurements after the last rollup are not aggregated yet, so they are rolled up here.
+			if tail := rollup.Hour.Next(rollups[len(rollups)-1].Bucket.Time()); tail.Before(to) {
+				samples, err := s.Repository.SelectRollupSample(ctx, id, internal.NewTimeValue(tail), before)
+				if err != nil {
+					return Stats{}, true, err
+				}
+				rollups = append(rollups, rollup.Aggregate(id, rollup.Hour, samples)...)
+			}
+			stats := FromRollups(rollups, from, to)
+			stats.MonitorId = id
+			return stats, true, nil
+		}
+	}
+
+	samples, err := s.Repository.SelectRollupSample(ctx, id, internal.NewTimeValue(from), internal.NewTimeValue(to))
+	if err != nil {
+		return Stats{}, true, err
+	}
+	var gap time.Duration
+	if found[0].Cron == nil && found[0].Interval > 0 {
+		gap = time.Duration(found[0].Interval*gapFactor) * time.Second
+	}
+	stats := FromSamples(samples, from, to, gap, maintenance)
+	stats.MonitorId = id
+	return stats, true, nil
+}
@@ -0,0 +1,214 @@
+package stats
+
+import (
+	"slices"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/rollup"
+)
+
+// Source is the kind of data that `Stats` are computed from.
+type Source string
+
+const (
+	// Raw statistics are computed from measurements, and are exact.
+	Raw Source = "RAW"
+	// Rollup statistics are computed from hourly rollups. Downtime, incidents and
+	// latency percentiles are estimated, and measurements taken during maintenance are always excluded.
+	Rollup Source = "ROLLUP"
+)
+
+// Stats is a summary of the availability of a monitor over a time range.
+type Stats struct {
+	MonitorId int                `json:"monitorId"`
+	From      internal.TimeValue `json:"from"`
+	To        internal.TimeValue `json:"to"`
+	Source    Source             `json:"source"`
+	// The number of measurements counted.
+	Measurements int `json:"measurements"`
+	// The percentage of measurements that are `Ok`, or nil without measurements.
+	Availability *float64 `json:"availability"`
+	// The percentage of measurements that are `Ok` or `Warn`, or nil without measurements.
+	AvailabilityWarn *float64 `json:"availabilityWarn"`
+	// The number of seconds the monitor was down.
+	Downtime float64 `json:"downtime"`
+	// The number of seconds the monitor was up.
+	Uptime float64 `json:"uptime"`
+	// The number of times the monitor went down.
+	Incidents int `json:"incidents"`
+	// The mean number of seconds to recover from an incident, or nil without incidents.
+	MTTR *float64 `json:"mttr"`
+	// The mean number of seconds between incidents, or nil without incidents.
+	MTBF    *float64 `json:"mtbf"`
+	Latency Latency  `json:"latency"`
+}
+
+// Latency describes the duration of measurements in milliseconds.
+type Latency struct {
+	Min float64 `json:"min"`
+	Avg float64 `json:"avg"`
+	Max float64 `json:"max"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// IsDown returns true if the state counts against availability.
+func IsDown(state measurement.ProbeState) bool {
+	return state == measurement.Dead || state == measurement.Unreachable
+}
+
+// FromSamples returns `Stats` computed from samples ordered by creation time.
+//
+// Each sample is assumed to hold until the next sample, or until `to` for the last sample.
+// Time between samples longer than `gap` is not counted as uptime or downtime,
+// unless `gap` is zero. Samples taken during maintenance are skipped when `maintenance` is false.
+//
+// Only `SUPPRESS` maintenance windows produce samples, so they are the only windows excluded.
+// A `PAUSE` window produces none, and its time is credited to the sample before it, up to `gap`.
+func FromSamples(samples []rollup.Sample, from time.Time, to time.Time, gap time.Duration, maintenance bool) Stats {
+	stats := Stats{From: internal.NewTimeValue(from), To: internal.NewTimeValue(to), Source: Raw}
+
+	var ok, warn int
+	durations := []float64{}
+	down := false
+	for i, v := range samples {
+		end := to
+		if i+1 < len(samples) {
+			end = samples[i+1].CreatedAt.Time()
+		}
+		span := end.Sub(v.CreatedAt.Time())
+		if gap > 0 && span > gap {
+			span = gap
+		}
+
+		if v.Maintenance && !maintenance {
+			continue
+		}
+
+		stats.Measurements++
+		durations = append(durations, v.Duration)
+		switch v.State {
+		case measurement.Ok:
+			ok++
+		case measurement.Warn:
+			warn++
+		}
+
+		if IsDown(v.State) {
+			if !down {
+				stats.Incidents++
+			}
+			stats.Downtime += span.Seconds()
+		} else {
+			stats.Uptime += span.Seconds()
+		}
+		down = IsDown(v.State)
+	}
+
+	if stats.Measurements > 0 {
+		stats.Availability = percentage(ok, stats.Measurements)
+		stats.AvailabilityWarn = percentage(ok+warn, stats.Measurements)
+
+		slices.Sort(durations)
+		stats.Latency = Latency{
+			Min: durations[0],
+			Avg: mean(durations),
+			Max: durations[len(durations)-1],
+			P95: rollup.Percentile(durations, 95),
+			P99: rollup.Percentile(durations, 99),
+		}
+	}
+	stats.mean()
+	return stats
+}
+
+// FromRollups returns `Stats` estimated from hourly rollups ordered by bucket.
+//
+// The time of each bucket is split between uptime and downtime by the share of
+// measurements that were down. Consecutive buckets with down measurements are one incident.
+func FromRollups(rollups []rollup.Rollup, from time.Time, to time.Time) Stats {
+	stats := Stats{From: internal.NewTimeValue(from), To: internal.NewTimeValue(to), Source: Rollup}
+
+	var ok, warn int
+	var sum, p95, p99 float64
+	down := false
+	for _, v := range rollups {
+		count := v.Count()
+		if count == 0 {
+			continue
+		}
+
+		start := v.Bucket.Time()
+		end := start.Add(time.Hour)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		// Time spent in maintenance is not counted.
+		span := end.Sub(start).Seconds() * float64(count) / float64(count+v.Maintenance)
+		dead := v.Dead + v.Unreachable
+		stats.Downtime += span * float64(dead) / float64(count)
+		stats.Uptime += span * float64(count-dead) / float64(count)
+		if dead > 0 && !down {
+			stats.Incidents++
+		}
+		down = dead > 0
+
+		if stats.Measurements == 0 || v.DurationMin < stats.Latency.Min {
+			stats.Latency.Min = v.DurationMin
+		}
+		if stats.Measurements == 0 || v.DurationMax > stats.Latency.Max {
+			stats.Latency.Max = v.DurationMax
+		}
+		weight := float64(count)
+		sum += v.DurationAvg * weight
+		// A percentile of the whole range can't be recovered from buckets,
+		// so the mean of the bucket percentiles is used.
+		p95 += v.DurationP95 * weight
+		p99 += v.DurationP99 * weight
+
+		stats.Measurements += count
+		ok += v.Ok
+		warn += v.Warn
+	}
+
+	if stats.Measurements > 0 {
+		stats.Availability = percentage(ok, stats.Measurements)
+		stats.AvailabilityWarn = percentage(ok+warn, stats.Measurements)
+
+		total := float64(stats.Measurements)
+		stats.Latency.Avg = sum / total
+		stats.Latency.P95 = p95 / total
+		stats.Latency.P99 = p99 / total
+	}
+	stats.mean()
+	return stats
+}
+
+// mean will set the mean time to recovery and between failures from the totals.
+func (s *Stats) mean() {
+	if s.Incidents == 0 {
+		return
+	}
+	mttr := s.Downtime / float64(s.Incidents)
+	mtbf := s.Uptime / float64(s.Incidents)
+	s.MTTR = &mttr
+	s.MTBF = &mtbf
+}
+
+func percentage(n int, total int) *float64 {
+	value := float64(n) / float64(total) * 100
+	return &value
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
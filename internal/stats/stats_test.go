@@ -0,0 +1,169 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/rollup"
+)
+
+func TestFromSamples(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) internal.TimeValue {
+		return internal.NewTimeValue(from.Add(time.Duration(minutes) * time.Minute))
+	}
+	samples := []rollup.Sample{
+		{CreatedAt: at(0), State: measurement.Ok, Duration: 10},
+		{CreatedAt: at(10), State: measurement.Dead, Duration: 40},
+		{CreatedAt: at(20), State: measurement.Dead, Duration: 30},
+		{CreatedAt: at(30), State: measurement.Warn, Duration: 20},
+		{CreatedAt: at(40), State: measurement.Dead, Duration: 90, Maintenance: true},
+		{CreatedAt: at(50), State: measurement.Ok, Duration: 10},
+	}
+	to := from.Add(time.Hour)
+
+	stats := FromSamples(samples, from, to, 0, false)
+	debug.AssertEqual(t, stats.Source, Raw)
+	debug.AssertEqual(t, stats.Measurements, 5)
+	debug.AssertEqual(t, *stats.Availability, 40.0)
+	debug.AssertEqual(t, *stats.AvailabilityWarn, 60.0)
+	debug.AssertEqual(t, stats.Downtime, (20 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Uptime, (30 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Incidents, 1)
+	debug.AssertEqual(t, *stats.MTTR, (20 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Latency.Max, 40.0)
+	debug.AssertEqual(t, stats.Latency.Avg, 22.0)
+
+	// The measurement taken during maintenance starts a second incident.
+	stats = FromSamples(samples, from, to, 0, true)
+	debug.AssertEqual(t, stats.Incidents, 2)
+	debug.AssertEqual(t, stats.Downtime, (30 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Latency.Max, 90.0)
+
+	// Time between measurements is limited by the gap.
+	stats = FromSamples(samples, from, to, 5*time.Minute, false)
+	debug.AssertEqual(t, stats.Downtime, (10 * time.Minute).Seconds())
+
+	// A paused monitor is not polled, so the pause is credited to the measurement before it.
+	paused := []rollup.Sample{
+		{CreatedAt: at(0), State: measurement.Ok, Duration: 10},
+		{CreatedAt: at(40), State: measurement.Dead, Duration: 10},
+	}
+	stats = FromSamples(paused, from, to, 0, false)
+	debug.AssertEqual(t, stats.Uptime, (40 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Downtime, (20 * time.Minute).Seconds())
+	stats = FromSamples(paused, from, to, 15*time.Minute, false)
+	debug.AssertEqual(t, stats.Uptime, (15 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Downtime, (15 * time.Minute).Seconds())
+
+	empty := FromSamples(nil, from, to, 0, false)
+	debug.Assert(t, empty.Availability == nil && empty.MTTR == nil, "expected no availability without measurements")
+}
+
+func TestFromRollups(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rollups := []rollup.Rollup{
+		{Bucket: internal.NewTimeValue(from), Ok: 3, Dead: 1, DurationMin: 5, DurationAvg: 10, DurationMax: 20, DurationP95: 20, DurationP99: 20},
+		{Bucket: internal.NewTimeValue(from.Add(time.Hour)), Dead: 2, Maintenance: 2, DurationMin: 1, DurationAvg: 30, DurationMax: 40, DurationP95: 40, DurationP99: 40},
+		{Bucket: internal.NewTimeValue(from.Add(2 * time.Hour)), Ok: 4, DurationMin: 2, DurationAvg: 10, DurationMax: 10, DurationP95: 10, DurationP99: 10},
+	}
+
+	stats := FromRollups(rollups, from, from.Add(3*time.Hour))
+	debug.AssertEqual(t, stats.Source, Rollup)
+	debug.AssertEqual(t, stats.Measurements, 10)
+	debug.AssertEqual(t, *stats.Availability, 70.0)
+	debug.AssertEqual(t, stats.Incidents, 1)
+	// A quarter of the first hour, and half of the second hour outside of maintenance.
+	debug.AssertEqual(t, stats.Downtime, (45 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Uptime, (105 * time.Minute).Seconds())
+	debug.AssertEqual(t, stats.Latency.Min, 1.0)
+	debug.AssertEqual(t, stats.Latency.Max, 40.0)
+	debug.AssertEqual(t, stats.Latency.Avg, 14.0)
+}
+
+// testRepository holds hourly rollups and the samples that were not pruned.
+type testRepository struct {
+	monitor.MonitorRepository
+	rollups []rollup.Rollup
+	samples []rollup.Sample
+}
+
+func (t testRepository) SelectMonitor(ctx context.Context, measurements int, params *monitor.SelectMonitorParams) ([]monitor.Monitor, error) {
+	id := 1
+	return []monitor.Monitor{{Id: &id, Interval: 600}}, nil
+}
+
+func (t testRepository) SelectRollup(ctx context.Context, id int, resolution rollup.Resolution, params *rollup.SelectRollupParams) ([]rollup.Rollup, error) {
+	found := []rollup.Rollup{}
+	for _, v := range t.rollups {
+		if !v.Bucket.Time().Before(params.After.Time()) && v.Bucket.Time().Before(params.Before.Time()) {
+			found = append(found, v)
+		}
+	}
+	return found, nil
+}
+
+func (t testRepository) SelectRollupLatest(ctx context.Context, id int, resolution rollup.Resolution) (*internal.TimeValue, error) {
+	return nil, nil
+}
+
+func (t testRepository) SelectRollupOrigin(ctx context.Context, id int, from internal.TimeValue) (*internal.TimeValue, error) {
+	for _, v := range t.samples {
+		if !v.CreatedAt.Time().Before(from.Time()) {
+			return &v.CreatedAt, nil
+		}
+	}
+	return nil, nil
+}
+
+func (t testRepository) SelectRollupSample(ctx context.Context, id int, from internal.TimeValue, to internal.TimeValue) ([]rollup.Sample, error) {
+	found := []rollup.Sample{}
+	for _, v := range t.samples {
+		if !v.CreatedAt.Time().Before(from.Time()) && v.CreatedAt.Time().Before(to.Time()) {
+			found = append(found, v)
+		}
+	}
+	return found, nil
+}
+
+func (t testRepository) UpsertRollup(ctx context.Context, rollups []rollup.Rollup) error {
+	return nil
+}
+
+func TestGetStatsRollupTail(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) internal.TimeValue {
+		return internal.NewTimeValue(from.Add(time.Duration(minutes) * time.Minute))
+	}
+	// Measurements were pruned before the third hour, which is not rolled up yet.
+	r := testRepository{
+		rollups: []rollup.Rollup{
+			{Bucket: at(0), Ok: 4, DurationMin: 10, DurationAvg: 10, DurationMax: 10},
+			{Bucket: at(60), Ok: 4, DurationMin: 10, DurationAvg: 10, DurationMax: 10},
+		},
+		samples: []rollup.Sample{
+			{CreatedAt: at(130), State: measurement.Dead, Duration: 50},
+			{CreatedAt: at(190), State: measurement.Ok, Duration: 10},
+			{CreatedAt: at(250), State: measurement.Ok, Duration: 10},
+		},
+	}
+	s := NewStatsService(r, r)
+
+	stats, found, err := s.GetStats(context.Background(), 1, from.Add(-time.Hour), at(270).Time(), false)
+	debug.Assert(t, err == nil && found, "expected stats")
+	debug.AssertEqual(t, stats.Source, Rollup)
+	debug.AssertEqual(t, stats.Measurements, 11)
+	debug.AssertEqual(t, stats.Incidents, 1)
+	debug.AssertEqual(t, stats.Latency.Max, 50.0)
+
+	// Rollups only count measurements taken during maintenance.
+	_, _, err = s.GetStats(context.Background(), 1, from.Add(-time.Hour), at(270).Time(), true)
+	debug.Assert(t, errors.As(err, &env.Validation{}), "expected validation error for maintenance")
+}
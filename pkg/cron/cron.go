@@ -0,0 +1,194 @@
+// Package cron implements parsing and scheduling of cron expressions.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the supported shorthand expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+var cronDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// cronField describes the bounds and names accepted by one field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: cronMonths},
+	// Both 0 and 7 are Sunday.
+	{name: "day of week", min: 0, max: 7, names: cronDays},
+}
+
+// Schedule is a parsed cron expression.
+//
+// Expressions have five fields (minute, hour, day of month, month and day of week),
+// each accepting `*`, values, ranges (`1-5`), steps (`*/15`, `0-30/10`) and lists (`1,15`).
+// Months and days of the week may also be given by their three letter names.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record if the day fields are unrestricted.
+	// When both are restricted, a day matches if either field matches.
+	domStar, dowStar bool
+	location         *time.Location
+}
+
+// Parse returns the `Schedule` for the expression, evaluated in the time zone.
+//
+// The time zone is an IANA name such as `Europe/Berlin`, and defaults to UTC when nil.
+func Parse(expression string, timezone *string) (Schedule, error) {
+	schedule := Schedule{location: time.UTC}
+	if timezone != nil {
+		location, err := time.LoadLocation(*timezone)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("unknown time zone `%v`", *timezone)
+		}
+		schedule.location = location
+	}
+
+	expression = strings.TrimSpace(expression)
+	if macro, ok := cronMacros[strings.ToLower(expression)]; ok {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return Schedule{}, fmt.Errorf("expected %v fields, found %v", len(cronFields), len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, v := range fields {
+		set, err := parseCronField(v, cronFields[i])
+		if err != nil {
+			return Schedule{}, err
+		}
+		bits[i] = set
+	}
+	schedule.minute, schedule.hour, schedule.dom, schedule.month, schedule.dow = bits[0], bits[1], bits[2], bits[3], bits[4]
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domStar = strings.HasPrefix(fields[2], "*")
+	schedule.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// parseCronField returns the set of values matched by the field, as a bit set.
+func parseCronField(value string, field cronField) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(value, ",") {
+		expression, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step `%v` in %v field", part[i+1:], field.name)
+			}
+			expression, step = part[:i], n
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case expression == "*":
+		case strings.Contains(expression, "-"):
+			bounds := strings.SplitN(expression, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], field); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(bounds[1], field); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range `%v` in %v field", expression, field.name)
+			}
+		default:
+			n, err := parseCronValue(expression, field)
+			if err != nil {
+				return 0, err
+			}
+			start = n
+			// A single value with a step, such as `5/15`, runs until the end of the range.
+			if step == 1 {
+				end = n
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			set |= 1 << uint(i)
+		}
+	}
+	return set, nil
+}
+
+// parseCronValue returns a single value of the field, accepting a number or a name.
+func parseCronValue(value string, field cronField) (int, error) {
+	for i, v := range field.names {
+		if v != "" && strings.EqualFold(value, v) {
+			return i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < field.min || n > field.max {
+		return 0, fmt.Errorf("invalid value `%v` in %v field, expected %v-%v", value, field.name, field.min, field.max)
+	}
+	return n, nil
+}
+
+// Next returns the first time after `t` matched by the schedule,
+// or the zero time if nothing matches within five years.
+func (c Schedule) Next(t time.Time) time.Time {
+	location := c.location
+	t = t.In(location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		next := t
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+		case !c.matchDay(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+		// Wall clock times that fall into a daylight saving transition can normalize backwards.
+		if !next.After(t) {
+			next = t.Add(time.Hour)
+		}
+		t = next
+	}
+
+	return time.Time{}
+}
+
+func (c Schedule) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
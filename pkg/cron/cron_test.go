@@ -0,0 +1,54 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestCronNext(t *testing.T) {
+	berlin := "Europe/Berlin"
+	from := time.Date(2024, time.March, 29, 17, 30, 0, 0, time.UTC) // Friday
+	cases := []struct {
+		expression string
+		timezone   *string
+		expect     time.Time
+	}{
+		{"*/15 * * * *", nil, time.Date(2024, time.March, 29, 17, 45, 0, 0, time.UTC)},
+		{"15 2 * * *", nil, time.Date(2024, time.March, 30, 2, 15, 0, 0, time.UTC)},
+		{"@hourly", nil, time.Date(2024, time.March, 29, 18, 0, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", nil, time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", nil, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 13 * 5", nil, time.Date(2024, time.April, 5, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", nil, time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", &berlin, time.Date(2024, time.March, 30, 1, 30, 0, 0, time.UTC)},
+		{"0 20 * * *", &berlin, time.Date(2024, time.March, 29, 19, 0, 0, 0, time.UTC)},
+	}
+
+	for _, v := range cases {
+		schedule, err := Parse(v.expression, v.timezone)
+		if err != nil {
+			t.Fatalf("failed to parse %v: %v", v.expression, err)
+		}
+		next := schedule.Next(from)
+		debug.AssertEqual(t, next.UTC(), v.expect)
+	}
+
+	// 02:30 does not exist in Berlin on 2024-03-31, the next run is the following day.
+	schedule, _ := Parse("30 2 * * *", &berlin)
+	debug.AssertEqual(t, schedule.Next(from.AddDate(0, 0, 1)).UTC(), time.Date(2024, time.April, 1, 0, 30, 0, 0, time.UTC))
+
+	schedule, _ = Parse("0 0 30 2 *", nil)
+	debug.Assert(t, schedule.Next(from).IsZero(), "expected zero time for impossible schedule")
+}
+
+func TestParseInvalid(t *testing.T) {
+	unknown := "Mars/Olympus_Mons"
+	for _, v := range []string{"* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "0 0 * foo *"} {
+		_, err := Parse(v, nil)
+		debug.Assert(t, err != nil, "expected error for expression", v)
+	}
+	_, err := Parse("* * * * *", &unknown)
+	debug.Assert(t, err != nil, "expected error for time zone", unknown)
+}
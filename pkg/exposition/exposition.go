@@ -0,0 +1,215 @@
+// Package exposition implements parsing of the Prometheus and OpenMetrics text exposition formats,
+// and selection of samples with label matchers.
+package exposition
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sample is a single sample line of an exposition.
+type Sample struct {
+	// Name is the name of the sample, including any suffix such as `_total` or `_bucket`.
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// maxLineSize is the longest line accepted by `Parse`.
+const maxLineSize = 1 << 20
+
+// Parse returns the samples of a text exposition, in the order they appear.
+//
+// Both the Prometheus text format (0.0.4) and OpenMetrics are accepted.
+// Comments, `HELP`, `TYPE` and `UNIT` lines are skipped, as are timestamps and exemplars.
+// Parsing stops at an OpenMetrics `# EOF` line.
+func Parse(r io.Reader) ([]Sample, error) {
+	samples := []Sample{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if line == "# EOF" {
+				break
+			}
+			continue
+		}
+		sample, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %w", number, err)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// parseSample parses a line in the form `name{label="value",...} value [timestamp] [# exemplar]`.
+func parseSample(line string) (Sample, error) {
+	sample := Sample{Labels: map[string]string{}}
+
+	end := strings.IndexAny(line, "{ \t")
+	if end == -1 {
+		return sample, errors.New("sample has no value")
+	}
+	sample.Name = line[:end]
+	if !isMetricName(sample.Name) {
+		return sample, fmt.Errorf("metric name `%v` is invalid", sample.Name)
+	}
+	rest := line[end:]
+
+	if strings.HasPrefix(rest, "{") {
+		labels, n, err := parseLabels(rest)
+		if err != nil {
+			return sample, err
+		}
+		sample.Labels = labels
+		rest = rest[n:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return sample, errors.New("sample has no value")
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return sample, fmt.Errorf("value `%v` is invalid", fields[0])
+	}
+	sample.Value = value
+	if len(fields) > 1 && fields[1] != "#" {
+		if _, err := strconv.ParseFloat(fields[1], 64); err != nil {
+			return sample, fmt.Errorf("timestamp `%v` is invalid", fields[1])
+		}
+		fields = fields[1:]
+	}
+	if len(fields) > 1 && fields[1] != "#" {
+		return sample, fmt.Errorf("unexpected `%v` after value", fields[1])
+	}
+
+	return sample, nil
+}
+
+// parseLabels parses a label set starting with `{`, and returns the labels
+// along with the number of bytes consumed, including the closing brace.
+func parseLabels(s string) (map[string]string, int, error) {
+	labels := map[string]string{}
+	i := 1
+	for {
+		i = skipSpace(s, i)
+		if i >= len(s) {
+			return nil, 0, errors.New("label set is not closed")
+		}
+		if s[i] == '}' {
+			return labels, i + 1, nil
+		}
+
+		start := i
+		for i < len(s) && isNameByte(s[i], i == start, false) {
+			i++
+		}
+		name := s[start:i]
+		if name == "" {
+			return nil, 0, fmt.Errorf("label name is missing at `%v`", s[start:])
+		}
+		i = skipSpace(s, i)
+		if i >= len(s) || s[i] != '=' {
+			return nil, 0, fmt.Errorf("label `%v` has no value", name)
+		}
+		i = skipSpace(s, i+1)
+
+		value, n, err := unquote(s[i:])
+		if err != nil {
+			return nil, 0, fmt.Errorf("label `%v`: %w", name, err)
+		}
+		if _, ok := labels[name]; ok {
+			return nil, 0, fmt.Errorf("label `%v` is repeated", name)
+		}
+		labels[name] = value
+		i = skipSpace(s, i+n)
+
+		if i < len(s) && s[i] == ',' {
+			i++
+		} else if i < len(s) && s[i] != '}' {
+			return nil, 0, fmt.Errorf("unexpected `%c` after label `%v`", s[i], name)
+		}
+	}
+}
+
+// unquote parses a double quoted string at the start of `s`, and returns the value
+// along with the number of bytes consumed.
+// The escape sequences `\\`, `\"` and `\n` are supported.
+func unquote(s string) (string, int, error) {
+	if s == "" || s[0] != '"' {
+		return "", 0, errors.New("value must be quoted")
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i >= len(s) {
+				return "", 0, errors.New("value is not closed")
+			}
+			switch s[i] {
+			case '\\', '"':
+				b.WriteByte(s[i])
+			case 'n':
+				b.WriteByte('\n')
+			default:
+				return "", 0, fmt.Errorf("escape sequence `\\%c` is invalid", s[i])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, errors.New("value is not closed")
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// isMetricName returns true if the name matches `[a-zA-Z_:][a-zA-Z0-9_:]*`.
+func isMetricName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isNameByte(name[i], i == 0, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNameByte returns true if the byte is valid in a metric or label name.
+// Colons are only valid in metric names.
+func isNameByte(c byte, first bool, colon bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c == ':':
+		return colon
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
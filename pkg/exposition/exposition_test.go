@@ -0,0 +1,138 @@
+package exposition
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+// parseFile returns the samples of a file in the testdata directory.
+func parseFile(t *testing.T, name string) []Sample {
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	samples, err := Parse(file)
+	if err != nil {
+		t.Fatalf("failed to parse %v: %v", name, err)
+	}
+	return samples
+}
+
+// selectOne returns the value of the only sample matching the selector.
+func selectOne(t *testing.T, samples []Sample, selector string) float64 {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		t.Fatalf("failed to parse selector %v: %v", selector, err)
+	}
+	selected := s.Select(samples)
+	if len(selected) != 1 {
+		t.Fatalf("expected one sample for %v, got %v", selector, len(selected))
+	}
+	return selected[0].Value
+}
+
+func TestParsePrometheus(t *testing.T) {
+	samples := parseFile(t, "prometheus.txt")
+	debug.AssertEqual(t, len(samples), 25)
+
+	debug.AssertEqual(t, selectOne(t, samples, `http_requests_total{method="post",code="400"}`), 3.0)
+	debug.AssertEqual(t, selectOne(t, samples, `metric_without_timestamp_and_labels`), 12.47)
+	debug.AssertEqual(t, selectOne(t, samples, `http_request_duration_seconds_bucket{le="+Inf"}`), 144320.0)
+	debug.AssertEqual(t, selectOne(t, samples, `rpc_duration_seconds_sum`), 1.7560473e+07)
+	debug.AssertEqual(t, selectOne(t, samples, `job_queue_depth{queue="reports"}`), 0.0)
+	debug.Assert(t, math.IsInf(selectOne(t, samples, `something_weird`), 1), "expected +Inf")
+	debug.Assert(t, math.IsNaN(selectOne(t, samples, `worker:utilization:ratio`)), "expected NaN")
+
+	escaped := Selector{Name: "msdos_file_access_time_seconds"}.Select(samples)
+	debug.AssertEqual(t, len(escaped), 1)
+	debug.AssertEqual(t, escaped[0].Labels["path"], `C:\DIR\FILE.TXT`)
+	debug.AssertEqual(t, escaped[0].Labels["error"], "Cannot find file:\n\"FILE.TXT\"")
+}
+
+func TestParseOpenMetrics(t *testing.T) {
+	samples := parseFile(t, "openmetrics.txt")
+	debug.AssertEqual(t, len(samples), 11)
+
+	debug.AssertEqual(t, selectOne(t, samples, `go_goroutines`), 69.0)
+	debug.AssertEqual(t, selectOne(t, samples, `process_cpu_seconds_total`), 4.20072246e+06)
+	debug.AssertEqual(t, selectOne(t, samples, `acme_http_router_request_seconds_count{path="/api/v2"}`), 34.0)
+	// The timestamp and exemplar are skipped.
+	debug.AssertEqual(t, selectOne(t, samples, `queue_errors_total{reason="timeout",queue="default"}`), 17.0)
+
+	s, _ := ParseSelector(`ignored_after_eof`)
+	debug.AssertEqual(t, len(s.Select(samples)), 0)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, v := range []string{
+		"no_value",
+		"no_value{a=\"b\"}",
+		"1name 1",
+		"bad_value abc",
+		"bad_timestamp 1 abc",
+		"trailing 1 2 3",
+		"unclosed{a=\"b\" 1",
+		"unquoted{a=b} 1",
+		"bad_escape{a=\"\\t\"} 1",
+		"repeated{a=\"b\",a=\"c\"} 1",
+		"missing_comma{a=\"b\" c=\"d\"} 1",
+	} {
+		_, err := Parse(strings.NewReader(v))
+		debug.Assert(t, err != nil, "expected error for line", v)
+	}
+
+	_, err := Parse(strings.NewReader("valid 1\ninvalid\n"))
+	debug.Assert(t, err != nil && strings.Contains(err.Error(), "line 2"), "expected error to include the line number")
+}
+
+func TestSelector(t *testing.T) {
+	samples := parseFile(t, "prometheus.txt")
+	cases := []struct {
+		selector string
+		expect   int
+	}{
+		{`http_requests_total`, 3},
+		{`http_requests_total{}`, 3},
+		{`http_requests_total{method="post"}`, 2},
+		{`http_requests_total{method!="post"}`, 1},
+		{`http_requests_total{code=~"[45].."}`, 2},
+		// Regular expressions are anchored.
+		{`http_requests_total{code=~"5"}`, 0},
+		{`http_requests_total{code!~"2.*", method = "post"}`, 1},
+		// A missing label has an empty value.
+		{`http_requests_total{missing=""}`, 3},
+		{`http_requests_total{missing!=""}`, 0},
+		{`job_queue_depth{queue=~"default|mail"}`, 2},
+		{`unknown_metric`, 0},
+	}
+
+	for _, v := range cases {
+		s, err := ParseSelector(v.selector)
+		if err != nil {
+			t.Fatalf("failed to parse selector %v: %v", v.selector, err)
+		}
+		debug.AssertEqual(t, len(s.Select(samples)), v.expect)
+	}
+}
+
+func TestParseSelectorInvalid(t *testing.T) {
+	for _, v := range []string{
+		"",
+		`{code="200"}`,
+		"1metric",
+		`metric{code}`,
+		`metric{code=200}`,
+		`metric{code=~"("}`,
+		`metric{code="200"`,
+		`metric{code="200"} extra`,
+		`metric extra`,
+	} {
+		_, err := ParseSelector(v)
+		debug.Assert(t, err != nil, "expected error for selector", v)
+	}
+}
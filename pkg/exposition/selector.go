@@ -0,0 +1,156 @@
+package exposition
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MatchType is the comparison performed by a `Matcher`.
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+// Matcher compares the value of a label.
+// A label that is not present has an empty value, as in PromQL.
+type Matcher struct {
+	Label string
+	Type  MatchType
+	Value string
+	re    *regexp.Regexp
+}
+
+// Matches returns true if the value satisfies the `Matcher`.
+func (m Matcher) Matches(value string) bool {
+	switch m.Type {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(value)
+	case MatchNotRegexp:
+		return !m.re.MatchString(value)
+	}
+	return false
+}
+
+// Selector is a parsed series selector.
+type Selector struct {
+	Name     string
+	Matchers []Matcher
+}
+
+// ParseSelector returns the `Selector` for a PromQL style series selector,
+// such as `http_requests_total{code=~"5..",method!="GET"}`.
+//
+// The metric name is required, and regular expressions are anchored at both ends.
+func ParseSelector(selector string) (Selector, error) {
+	s := strings.TrimSpace(selector)
+	var result Selector
+
+	end := strings.IndexAny(s, "{ \t")
+	if end == -1 {
+		end = len(s)
+	}
+	result.Name = s[:end]
+	if !isMetricName(result.Name) {
+		return result, fmt.Errorf("metric name `%v` is invalid", result.Name)
+	}
+
+	rest := strings.TrimSpace(s[end:])
+	if rest == "" {
+		return result, nil
+	}
+	if rest[0] != '{' {
+		return result, fmt.Errorf("unexpected `%v` after metric name", rest)
+	}
+
+	i := 1
+	for {
+		i = skipSpace(rest, i)
+		if i >= len(rest) {
+			return result, errors.New("label matchers are not closed")
+		}
+		if rest[i] == '}' {
+			if tail := strings.TrimSpace(rest[i+1:]); tail != "" {
+				return result, fmt.Errorf("unexpected `%v` after label matchers", tail)
+			}
+			return result, nil
+		}
+
+		start := i
+		for i < len(rest) && isNameByte(rest[i], i == start, false) {
+			i++
+		}
+		label := rest[start:i]
+		if label == "" {
+			return result, fmt.Errorf("label name is missing at `%v`", rest[start:])
+		}
+		i = skipSpace(rest, i)
+
+		var kind MatchType
+		for _, v := range []MatchType{MatchRegexp, MatchNotRegexp, MatchNotEqual, MatchEqual} {
+			if strings.HasPrefix(rest[i:], string(v)) {
+				kind = v
+				break
+			}
+		}
+		if kind == "" {
+			return result, fmt.Errorf("label `%v` has no operator", label)
+		}
+		i = skipSpace(rest, i+len(kind))
+
+		value, n, err := unquote(rest[i:])
+		if err != nil {
+			return result, fmt.Errorf("label `%v`: %w", label, err)
+		}
+		i = skipSpace(rest, i+n)
+
+		matcher := Matcher{Label: label, Type: kind, Value: value}
+		if kind == MatchRegexp || kind == MatchNotRegexp {
+			matcher.re, err = regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return result, fmt.Errorf("label `%v` regular expression is invalid: %w", label, err)
+			}
+		}
+		result.Matchers = append(result.Matchers, matcher)
+
+		if i < len(rest) && rest[i] == ',' {
+			i++
+		} else if i < len(rest) && rest[i] != '}' {
+			return result, fmt.Errorf("unexpected `%c` after label `%v`", rest[i], label)
+		}
+	}
+}
+
+// Matches returns true if the sample has the name of the `Selector`,
+// and satisfies every matcher.
+func (s Selector) Matches(sample Sample) bool {
+	if sample.Name != s.Name {
+		return false
+	}
+	for _, v := range s.Matchers {
+		if !v.Matches(sample.Labels[v.Label]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the samples that match the `Selector`.
+func (s Selector) Select(samples []Sample) []Sample {
+	selected := []Sample{}
+	for _, v := range samples {
+		if s.Matches(v) {
+			selected = append(selected, v)
+		}
+	}
+	return selected
+}
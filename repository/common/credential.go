@@ -0,0 +1,70 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmkng/zenin/internal/credential"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+func (c CommonRepository) SelectCredential(ctx context.Context, builder *zsql.Builder, params *credential.SelectCredentialParams) ([]credential.Credential, error) {
+	credentials := []credential.Credential{}
+
+	builder.Push(`SELECT
+        id "credential_id",
+        created_at,
+        updated_at,
+        name,
+        certificate,
+        private_key,
+        ca_bundle
+    FROM credential`)
+	if params != nil {
+		builder.Inject(params)
+	}
+	builder.Push(" ORDER BY id")
+
+	err := c.db.SelectContext(ctx, &credentials, builder.String(), builder.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select credential: %w", err)
+	}
+
+	return credentials, nil
+}
+
+func (c CommonRepository) UpdateCredential(ctx context.Context, builder *zsql.Builder, credential credential.Credential) error {
+	builder.Push("UPDATE credential SET updated_at = ")
+	builder.BindOpaque(credential.UpdatedAt)
+	builder.Push(", name = ")
+	builder.BindString(credential.Name)
+	builder.Push(", certificate = ")
+	builder.BindOpaque(credential.Certificate)
+	builder.Push(", private_key = ")
+	builder.BindOpaque(credential.PrivateKey)
+	builder.Push(", ca_bundle = ")
+	builder.BindOpaque(credential.CABundle)
+	builder.Push(" WHERE id = ")
+	builder.BindInt(*credential.Id)
+
+	_, err := c.db.ExecContext(ctx, builder.String(), builder.Args()...)
+	if err != nil {
+		return fmt.Errorf("failed to update credential: %w", err)
+	}
+
+	return nil
+}
+
+func (c CommonRepository) DeleteCredential(ctx context.Context, builder *zsql.Builder, id []int) error {
+	builder.Push("DELETE FROM credential WHERE id IN (")
+	builder.SpreadInt(id...)
+	builder.Push(")")
+
+	_, err := c.db.ExecContext(ctx, builder.String(), builder.Args()...)
+	if err != nil {
+		return fmt.Errorf("failed to delete credential: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmkng/zenin/internal/maintenance"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+func (c CommonRepository) SelectMaintenance(ctx context.Context, builder *zsql.Builder, params *maintenance.SelectMaintenanceParams) ([]maintenance.Maintenance, error) {
+	windows := []maintenance.Maintenance{}
+
+	builder.Push(`SELECT
+        id "maintenance_id",
+        created_at,
+        updated_at,
+        name,
+        description,
+        mode,
+        starts_at,
+        ends_at,
+        recurrence,
+        timezone,
+        duration
+    FROM maintenance`)
+	if params != nil {
+		builder.Inject(params)
+	}
+	builder.Push(" ORDER BY id")
+
+	err := c.db.SelectContext(ctx, &windows, builder.String(), builder.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select maintenance: %w", err)
+	}
+	if len(windows) == 0 {
+		return windows, nil
+	}
+
+	store := make(map[int]*maintenance.Maintenance)
+	var distinct []int
+	for i, v := range windows {
+		windows[i].MonitorId = []int{}
+		distinct = append(distinct, *v.Id)
+		store[*v.Id] = &windows[i]
+	}
+
+	builder.Reset()
+	builder.Push("SELECT maintenance_id, monitor_id FROM maintenance_monitor WHERE maintenance_id IN (")
+	builder.SpreadInt(distinct...)
+	builder.Push(") ORDER BY monitor_id")
+
+	var related []struct {
+		MaintenanceId int `db:"maintenance_id"`
+		MonitorId     int `db:"monitor_id"`
+	}
+	err = c.db.SelectContext(ctx, &related, builder.String(), builder.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select maintenance monitors: %w", err)
+	}
+	for _, v := range related {
+		owner := store[v.MaintenanceId]
+		owner.MonitorId = append(owner.MonitorId, v.MonitorId)
+	}
+
+	return windows, nil
+}
+
+// ReplaceMaintenanceMonitors will replace the monitors attached to the maintenance window.
+func (c CommonRepository) ReplaceMaintenanceMonitors(ctx context.Context, tx *sql.Tx, builder *zsql.Builder, id int, monitors []int) error {
+	builder.Push("DELETE FROM maintenance_monitor WHERE maintenance_id = ")
+	builder.BindInt(id)
+	if _, err := tx.ExecContext(ctx, builder.String(), builder.Args()...); err != nil {
+		return fmt.Errorf("failed to delete maintenance monitors: %w", err)
+	}
+
+	for _, v := range monitors {
+		builder.Reset()
+		builder.Push("INSERT INTO maintenance_monitor (maintenance_id, monitor_id) VALUES (")
+		builder.SpreadInt(id, v)
+		builder.Push(")")
+		if _, err := tx.ExecContext(ctx, builder.String(), builder.Args()...); err != nil {
+			return fmt.Errorf("failed to insert maintenance monitor: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c CommonRepository) DeleteMaintenance(ctx context.Context, builder *zsql.Builder, id []int) error {
+	builder.Push("DELETE FROM maintenance WHERE id IN (")
+	builder.SpreadInt(id...)
+	builder.Push(")")
+
+	_, err := c.db.ExecContext(ctx, builder.String(), builder.Args()...)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance: %w", err)
+	}
+
+	return nil
+}
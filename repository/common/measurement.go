@@ -2,6 +2,8 @@ package common
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/jmkng/zenin/internal/measurement"
 
@@ -42,3 +44,22 @@ func (c CommonRepository) DeleteMeasurement(ctx context.Context, builder *zsql.B
 	_, err := c.db.ExecContext(ctx, builder.String(), builder.Args()...)
 	return err
 }
+
+func (c CommonRepository) SelectLastState(ctx context.Context, builder *zsql.Builder, id int) (*measurement.ProbeState, error) {
+	builder.Push(`SELECT state
+	FROM measurement
+	WHERE retry_attempt IS NULL AND state <> 'UNREACHABLE' AND NOT maintenance AND monitor_id = `)
+	builder.BindInt(id)
+	builder.Push("ORDER BY id DESC LIMIT 1")
+
+	var state measurement.ProbeState
+	err := c.db.GetContext(ctx, &state, builder.String(), builder.Args()...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"sort"
 
@@ -37,7 +38,8 @@ func (c CommonRepository) SelectMonitor(ctx context.Context, measurements int, p
 		monitor_id "event_monitor_id",
         plugin_name,
         plugin_args,
-        threshold
+        threshold,
+        trigger_mode
     FROM event
     WHERE monitor_id IN (`)
 	builder.SpreadInt(distinct...)
@@ -58,6 +60,24 @@ func (c CommonRepository) SelectMonitor(ctx context.Context, measurements int, p
 		owner.Events = append(owner.Events, v)
 	}
 
+	builder.Reset()
+	builder.Push("SELECT monitor_id, parent_id FROM monitor_dependency WHERE monitor_id IN (")
+	builder.SpreadInt(distinct...)
+	builder.Push(") ORDER BY parent_id")
+
+	dependencies := []struct {
+		MonitorId int `db:"monitor_id"`
+		ParentId  int `db:"parent_id"`
+	}{}
+	err = c.db.SelectContext(ctx, &dependencies, builder.String(), builder.Args()...)
+	if err != nil {
+		return []monitor.Monitor{}, err
+	}
+	for _, v := range dependencies {
+		owner := store[v.MonitorId]
+		owner.ParentId = append(owner.ParentId, v.ParentId)
+	}
+
 	result := []monitor.Monitor{}
 	for _, v := range store {
 		result = append(result, *v)
@@ -87,7 +107,19 @@ func (c CommonRepository) SelectMeasurement(ctx context.Context, id int, params
 		icmp_max_rtt,
 		plugin_exit_code,
 		plugin_stdout,
-		plugin_stderr
+		plugin_stderr,
+		dns_answers,
+		dns_rtt,
+		http_dns_duration,
+		http_connect_duration,
+		http_tls_duration,
+		http_first_byte_duration,
+		http_transfer_duration,
+		http_redirects,
+		retry_attempt,
+		flapping,
+		maintenance,
+		metric_value
 	FROM measurement`)
 	builder.Push(fmt.Sprintf("%v monitor_id = ", builder.Where()))
 	builder.BindInt(id)
@@ -106,6 +138,27 @@ func (c CommonRepository) SelectMeasurement(ctx context.Context, id int, params
 	return measurements, nil
 }
 
+// ReplaceMonitorParents will replace the parents of the monitor with the given id.
+func (c CommonRepository) ReplaceMonitorParents(ctx context.Context, tx *sql.Tx, builder *zsql.Builder, id int, parents []int) error {
+	builder.Push("DELETE FROM monitor_dependency WHERE monitor_id = ")
+	builder.BindInt(id)
+	if _, err := tx.ExecContext(ctx, builder.String(), builder.Args()...); err != nil {
+		return fmt.Errorf("failed to delete monitor dependencies: %w", err)
+	}
+
+	for _, v := range parents {
+		builder.Reset()
+		builder.Push("INSERT INTO monitor_dependency (monitor_id, parent_id) VALUES (")
+		builder.SpreadInt(id, v)
+		builder.Push(")")
+		if _, err := tx.ExecContext(ctx, builder.String(), builder.Args()...); err != nil {
+			return fmt.Errorf("failed to insert monitor dependency: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (c CommonRepository) DeleteMonitor(ctx context.Context, builder *zsql.Builder, id []int) error {
 	builder.Push("DELETE FROM monitor WHERE id in (")
 	builder.SpreadInt(id...)
@@ -146,7 +199,46 @@ func (c CommonRepository) selectMonitor(ctx context.Context, params *monitor.Sel
 			mo.icmp_count,
 			mo.icmp_ttl,
 			mo.icmp_protocol,
-            mo.icmp_loss_threshold
+            mo.icmp_loss_threshold,
+			mo.dns_record_type,
+			mo.dns_resolver,
+			mo.dns_expected_answers,
+			mo.http_cert_expiry_days,
+			mo.http_assertions,
+			mo.http_follow_redirects,
+			mo.http_max_redirects,
+			mo.http_skip_verify,
+			mo.http_proxy,
+			mo.http_min_tls_version,
+			mo.http_version,
+			mo.credential_id,
+			mo.tcp_send,
+			mo.tcp_expect,
+			mo.tcp_match,
+			mo.tcp_read_limit,
+			mo.tcp_tls,
+			mo.tcp_server_name,
+			mo.tcp_skip_verify,
+			mo.udp_send,
+			mo.udp_expect_reply,
+			mo.udp_expect,
+			mo.udp_match,
+			mo.push_token,
+			mo.push_grace,
+			mo.cron,
+			mo.cron_timezone,
+			mo.retry_count,
+			mo.retry_interval,
+			mo.flap_threshold,
+			mo.flap_window,
+			mo.retention_days,
+			mo.retention_rows,
+			mo.metric_selector,
+			mo.metric_aggregate,
+			mo.metric_condition,
+			mo.metric_warn,
+			mo.metric_dead,
+			COALESCE((SELECT me.flapping FROM measurement me WHERE me.monitor_id = mo.id ORDER BY me.id DESC LIMIT 1), FALSE) AS flapping
         FROM monitor mo`)
 	if params != nil {
 		builder.Inject(params)
@@ -185,7 +277,46 @@ func (c CommonRepository) selectMonitorRelated(ctx context.Context, params *moni
 		icmp_count, 
 		icmp_ttl, 
 		icmp_protocol,
-        icmp_loss_threshold
+        icmp_loss_threshold,
+		dns_record_type,
+		dns_resolver,
+		dns_expected_answers,
+		http_cert_expiry_days,
+		http_assertions,
+		http_follow_redirects,
+		http_max_redirects,
+		http_skip_verify,
+		http_proxy,
+		http_min_tls_version,
+		http_version,
+		credential_id,
+		tcp_send,
+		tcp_expect,
+		tcp_match,
+		tcp_read_limit,
+		tcp_tls,
+		tcp_server_name,
+		tcp_skip_verify,
+		udp_send,
+		udp_expect_reply,
+		udp_expect,
+		udp_match,
+		push_token,
+		push_grace,
+		cron,
+		cron_timezone,
+		retry_count,
+		retry_interval,
+		flap_threshold,
+		flap_window,
+		retention_days,
+		retention_rows,
+		metric_selector,
+		metric_aggregate,
+		metric_condition,
+		metric_warn,
+		metric_dead,
+		COALESCE((SELECT me.flapping FROM measurement me WHERE me.monitor_id = monitor.id ORDER BY me.id DESC LIMIT 1), FALSE) AS flapping
 	FROM monitor`)
 	if params != nil {
 		builder.Inject(params)
@@ -232,7 +363,19 @@ func (c CommonRepository) selectMonitorRelated(ctx context.Context, params *moni
 		icmp_max_rtt,
 		plugin_exit_code, 
 		plugin_stdout, 
-		plugin_stderr
+		plugin_stderr,
+		dns_answers,
+		dns_rtt,
+		http_dns_duration,
+		http_connect_duration,
+		http_tls_duration,
+		http_first_byte_duration,
+		http_transfer_duration,
+		http_redirects,
+		retry_attempt,
+		flapping,
+		maintenance,
+		metric_value
 	FROM raw WHERE rank <=`)
 	builder.BindInt(measurements)
 
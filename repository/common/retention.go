@@ -0,0 +1,45 @@
+package common
+
+import (
+	"context"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// DeleteMeasurementBefore deletes expired measurements. The cutoff must be in the
+// format used by the created_at column of the backend.
+func (c CommonRepository) DeleteMeasurementBefore(ctx context.Context, builder *zsql.Builder, id int, cutoff any, limit int) (int, error) {
+	builder.Push(`DELETE FROM measurement WHERE id IN (
+		SELECT id FROM measurement WHERE monitor_id = `)
+	builder.BindInt(id)
+	builder.Push("AND created_at < ")
+	builder.BindOpaque(cutoff)
+	builder.Push("ORDER BY id LIMIT ")
+	builder.BindInt(limit)
+	builder.Push(")")
+
+	return c.exec(ctx, builder)
+}
+
+func (c CommonRepository) DeleteMeasurementBeyond(ctx context.Context, builder *zsql.Builder, id int, keep int, limit int) (int, error) {
+	builder.Push(`DELETE FROM measurement WHERE id IN (
+		SELECT id FROM measurement WHERE monitor_id = `)
+	builder.BindInt(id)
+	builder.Push("ORDER BY id DESC LIMIT ")
+	builder.BindInt(limit)
+	builder.Push("OFFSET ")
+	builder.BindInt(keep)
+	builder.Push(")")
+
+	return c.exec(ctx, builder)
+}
+
+// exec will execute the statement in the builder, and return the number of rows affected.
+func (c CommonRepository) exec(ctx context.Context, builder *zsql.Builder) (int, error) {
+	result, err := c.db.ExecContext(ctx, builder.String(), builder.Args()...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/rollup"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+func (c CommonRepository) SelectRollup(ctx context.Context, builder *zsql.Builder, id int, resolution rollup.Resolution, params *rollup.SelectRollupParams) ([]rollup.Rollup, error) {
+	builder.Push(`SELECT
+		monitor_id,
+		resolution,
+		bucket,
+		count_ok,
+		count_warn,
+		count_dead,
+		count_unreachable,
+		count_maintenance,
+		duration_min,
+		duration_avg,
+		duration_max,
+		duration_p95,
+		duration_p99,
+		icmp_avg_rtt,
+		icmp_loss
+	FROM measurement_rollup`)
+	builder.Push(fmt.Sprintf("%v monitor_id = ", builder.Where()))
+	builder.BindInt(id)
+	builder.Push(fmt.Sprintf("%v resolution = ", builder.Where()))
+	builder.BindString(string(resolution))
+	if params != nil {
+		builder.Inject(params)
+	}
+	builder.Push("ORDER BY bucket")
+
+	var rollups []rollup.Rollup
+	err := c.db.SelectContext(ctx, &rollups, builder.String(), builder.Args()...)
+	return rollups, err
+}
+
+func (c CommonRepository) SelectRollupLatest(ctx context.Context, builder *zsql.Builder, id int, resolution rollup.Resolution) (*internal.TimeValue, error) {
+	builder.Push("SELECT MAX(bucket) FROM measurement_rollup WHERE monitor_id = ")
+	builder.BindInt(id)
+	builder.Push("AND resolution = ")
+	builder.BindString(string(resolution))
+
+	var latest *internal.TimeValue
+	err := c.db.QueryRowContext(ctx, builder.String(), builder.Args()...).Scan(&latest)
+	return latest, err
+}
+
+// SelectRollupOrigin returns the creation time of the oldest measurement created at or after
+// `from`. The time must be in the format used by the created_at column of the backend.
+func (c CommonRepository) SelectRollupOrigin(ctx context.Context, builder *zsql.Builder, id int, from any) (*internal.TimeValue, error) {
+	builder.Push("SELECT MIN(created_at) FROM measurement WHERE monitor_id = ")
+	builder.BindInt(id)
+	builder.Push("AND retry_attempt IS NULL AND created_at >= ")
+	builder.BindOpaque(from)
+
+	var origin *internal.TimeValue
+	err := c.db.QueryRowContext(ctx, builder.String(), builder.Args()...).Scan(&origin)
+	return origin, err
+}
+
+// SelectRollupSample returns the samples created at or after `from` and before `to`.
+// The times must be in the format used by the created_at column of the backend.
+func (c CommonRepository) SelectRollupSample(ctx context.Context, builder *zsql.Builder, id int, from any, to any) ([]rollup.Sample, error) {
+	builder.Push(`SELECT
+		created_at,
+		state,
+		COALESCE(duration, 0) AS "duration",
+		icmp_packets_in,
+		icmp_packets_out,
+		icmp_avg_rtt,
+		maintenance
+	FROM measurement WHERE monitor_id = `)
+	builder.BindInt(id)
+	builder.Push("AND retry_attempt IS NULL AND created_at >= ")
+	builder.BindOpaque(from)
+	builder.Push("AND created_at < ")
+	builder.BindOpaque(to)
+	builder.Push("ORDER BY created_at, id")
+
+	var samples []rollup.Sample
+	err := c.db.SelectContext(ctx, &samples, builder.String(), builder.Args()...)
+	return samples, err
+}
+
+func (c CommonRepository) UpsertRollup(ctx context.Context, builder *zsql.Builder, rollups []rollup.Rollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, v := range rollups {
+		builder.Reset()
+		builder.Push(`INSERT INTO measurement_rollup (
+			monitor_id,
+			resolution,
+			bucket,
+			count_ok,
+			count_warn,
+			count_dead,
+			count_unreachable,
+			count_maintenance,
+			duration_min,
+			duration_avg,
+			duration_max,
+			duration_p95,
+			duration_p99,
+			icmp_avg_rtt,
+			icmp_loss
+		) VALUES (`)
+		builder.SpreadOpaque(v.MonitorId,
+			v.Resolution,
+			v.Bucket,
+			v.Ok,
+			v.Warn,
+			v.Dead,
+			v.Unreachable,
+			v.Maintenance,
+			v.DurationMin,
+			v.DurationAvg,
+			v.DurationMax,
+			v.DurationP95,
+			v.DurationP99,
+			v.ICMPAvgRTT,
+			v.ICMPLoss)
+		builder.Push(`) ON CONFLICT (monitor_id, resolution, bucket) DO UPDATE SET
+			count_ok = excluded.count_ok,
+			count_warn = excluded.count_warn,
+			count_dead = excluded.count_dead,
+			count_unreachable = excluded.count_unreachable,
+			count_maintenance = excluded.count_maintenance,
+			duration_min = excluded.duration_min,
+			duration_avg = excluded.duration_avg,
+			duration_max = excluded.duration_max,
+			duration_p95 = excluded.duration_p95,
+			duration_p99 = excluded.duration_p99,
+			icmp_avg_rtt = excluded.icmp_avg_rtt,
+			icmp_loss = excluded.icmp_loss`)
+
+		if _, err := tx.ExecContext(ctx, builder.String(), builder.Args()...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert rollup: %w", err)
+		}
+	}
+	return tx.Commit()
+}
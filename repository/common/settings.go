@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/jmkng/zenin/internal"
 	"github.com/jmkng/zenin/internal/settings"
@@ -38,6 +39,19 @@ func (c CommonRepository) SelectSettings(ctx context.Context) (settings.Settings
 				return settings.Settings{}, fmt.Errorf("failed to unmarshal delimiters: %w", err)
 			}
 			result.Delimiters = &delimiters
+		case settings.RetentionDaysKey, settings.RetentionRowsKey:
+			if text == nil {
+				continue
+			}
+			value, err := strconv.Atoi(*text)
+			if err != nil {
+				return settings.Settings{}, fmt.Errorf("failed to parse %v: %w", key, err)
+			}
+			if key == settings.RetentionDaysKey {
+				result.RetentionDays = &value
+			} else {
+				result.RetentionRows = &value
+			}
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestCredential(t *testing.T) {
+	if _, set := os.LookupEnv(SkipKey); !set {
+		skip(t)
+	}
+	repository := fixture(t)
+
+	ctx := context.Background()
+	certificate := "certificate"
+	key := "encrypted"
+	bundle := "bundle"
+	id, err := repository.InsertCredential(ctx, credential.Credential{
+		CreatedAt:   internal.NewTimeValue(time.Now()),
+		UpdatedAt:   internal.NewTimeValue(time.Now()),
+		Name:        "Internal",
+		Certificate: &certificate,
+		PrivateKey:  &key,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := repository.SelectCredential(ctx, &credential.SelectCredentialParams{Id: &[]int{id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(found), 1)
+	debug.AssertEqual(t, found[0].Name, "Internal")
+	debug.AssertEqual(t, *found[0].PrivateKey, key)
+	debug.Assert(t, found[0].CABundle == nil, "expected no ca bundle")
+
+	found[0].Name = "Private PKI"
+	found[0].Certificate = nil
+	found[0].PrivateKey = nil
+	found[0].CABundle = &bundle
+	if err := repository.UpdateCredential(ctx, found[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err = repository.SelectCredential(ctx, &credential.SelectCredentialParams{Id: &[]int{id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, found[0].Name, "Private PKI")
+	debug.Assert(t, found[0].PrivateKey == nil, "expected private key to be removed")
+	debug.AssertEqual(t, *found[0].CABundle, bundle)
+
+	if err := repository.DeleteCredential(ctx, []int{id}); err != nil {
+		t.Fatal(err)
+	}
+	found, err = repository.SelectCredential(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(found), 0)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/maintenance"
+)
+
+func TestMaintenance(t *testing.T) {
+	if _, set := os.LookupEnv(SkipKey); !set {
+		skip(t)
+	}
+	repository := fixture(t)
+
+	ctx := context.Background()
+	now := time.Now()
+	ends := internal.NewTimeValue(now.Add(time.Hour))
+	id, err := repository.InsertMaintenance(ctx, maintenance.Maintenance{
+		CreatedAt: internal.NewTimeValue(now),
+		UpdatedAt: internal.NewTimeValue(now),
+		Name:      "Database upgrade",
+		Mode:      maintenance.Pause,
+		StartsAt:  internal.NewTimeValue(now),
+		EndsAt:    &ends,
+		MonitorId: []int{1, 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := repository.SelectMaintenance(ctx, &maintenance.SelectMaintenanceParams{Id: &[]int{id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(found), 1)
+	debug.AssertEqual(t, found[0].Name, "Database upgrade")
+	debug.AssertEqual(t, found[0].EndsAt.Time().Unix(), ends.Time().Unix())
+	debug.AssertDeepEqual(t, found[0].MonitorId, []int{1, 2})
+
+	recurrence := "0 2 * * sat"
+	duration := 7200
+	found[0].Mode = maintenance.Suppress
+	found[0].EndsAt = nil
+	found[0].Recurrence = &recurrence
+	found[0].Duration = &duration
+	found[0].MonitorId = []int{3}
+	if err := repository.UpdateMaintenance(ctx, found[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err = repository.SelectMaintenance(ctx, &maintenance.SelectMaintenanceParams{Id: &[]int{id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, found[0].Mode, maintenance.Suppress)
+	debug.Assert(t, found[0].EndsAt == nil, "expected no end")
+	debug.AssertEqual(t, *found[0].Recurrence, recurrence)
+	debug.AssertEqual(t, *found[0].Duration, duration)
+	debug.AssertDeepEqual(t, found[0].MonitorId, []int{3})
+
+	if err := repository.DeleteMaintenance(ctx, []int{id}); err != nil {
+		t.Fatal(err)
+	}
+	found, err = repository.SelectMaintenance(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(found), 0)
+}
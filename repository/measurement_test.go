@@ -105,3 +105,36 @@ func TestSelectCertificate(t *testing.T) {
 
 	debug.AssertEqual(t, len(certificates), 3)
 }
+
+func TestSelectLastState(t *testing.T) {
+	if _, set := os.LookupEnv(SkipKey); !set {
+		skip(t)
+	}
+	repository := fixture(t)
+
+	ctx := context.Background()
+	mid := 4
+	attempt := 1
+	for _, v := range []measurement.Measurement{
+		{MonitorId: &mid, Span: measurement.Span{State: measurement.Dead, Kind: measurement.HTTP}},
+		{MonitorId: &mid, RetryAttempt: &attempt, Span: measurement.Span{State: measurement.Warn, Kind: measurement.HTTP}},
+		{MonitorId: &mid, Maintenance: true, Span: measurement.Span{State: measurement.Ok, Kind: measurement.HTTP}},
+	} {
+		if _, err := repository.InsertMeasurement(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state, err := repository.SelectLastState(ctx, mid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.Assert(t, state != nil, "expected state")
+	debug.AssertEqual(t, *state, measurement.Dead)
+
+	state, err = repository.SelectLastState(ctx, 999)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.Assert(t, state == nil, "expected nil state for monitor without measurements")
+}
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"github.com/jmkng/zenin/internal/credential"
+	"golang.org/x/net/context"
+)
+
+// SelectCredential implements `CredentialRepository.SelectCredential` for `MockRepository`.
+func (m MockRepository) SelectCredential(ctx context.Context, params *credential.SelectCredentialParams) ([]credential.Credential, error) {
+	return nil, nil
+}
+
+// InsertCredential implements `CredentialRepository.InsertCredential` for `MockRepository`.
+func (m MockRepository) InsertCredential(ctx context.Context, credential credential.Credential) (int, error) {
+	return -1, nil
+}
+
+// UpdateCredential implements `CredentialRepository.UpdateCredential` for `MockRepository`.
+func (m MockRepository) UpdateCredential(ctx context.Context, credential credential.Credential) error {
+	return nil
+}
+
+// DeleteCredential implements `CredentialRepository.DeleteCredential` for `MockRepository`.
+func (m MockRepository) DeleteCredential(ctx context.Context, id []int) error {
+	return nil
+}
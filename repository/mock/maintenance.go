@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"github.com/jmkng/zenin/internal/maintenance"
+	"golang.org/x/net/context"
+)
+
+// SelectMaintenance implements `MaintenanceRepository.SelectMaintenance` for `MockRepository`.
+func (m MockRepository) SelectMaintenance(ctx context.Context, params *maintenance.SelectMaintenanceParams) ([]maintenance.Maintenance, error) {
+	return nil, nil
+}
+
+// InsertMaintenance implements `MaintenanceRepository.InsertMaintenance` for `MockRepository`.
+func (m MockRepository) InsertMaintenance(ctx context.Context, maintenance maintenance.Maintenance) (int, error) {
+	return -1, nil
+}
+
+// UpdateMaintenance implements `MaintenanceRepository.UpdateMaintenance` for `MockRepository`.
+func (m MockRepository) UpdateMaintenance(ctx context.Context, maintenance maintenance.Maintenance) error {
+	return nil
+}
+
+// DeleteMaintenance implements `MaintenanceRepository.DeleteMaintenance` for `MockRepository`.
+func (m MockRepository) DeleteMaintenance(ctx context.Context, id []int) error {
+	return nil
+}
@@ -19,3 +19,8 @@ func (m MockRepository) SelectCertificate(ctx context.Context, id int) ([]measur
 func (m MockRepository) DeleteMeasurement(ctx context.Context, id []int) error {
 	return nil
 }
+
+// SelectLastState implements `MeasurementRepository.SelectLastState` for `MockRepository`.
+func (m MockRepository) SelectLastState(ctx context.Context, id int) (*measurement.ProbeState, error) {
+	return nil, nil
+}
@@ -22,6 +22,11 @@ func (m MockRepository) Fixture() error {
 	return nil
 }
 
+// Close implements `Repository.Close` for `MockRepository`.
+func (m MockRepository) Close() error {
+	return nil
+}
+
 // Describe implements `Repository.Describe` for `MockRepository`.
 func (m MockRepository) Describe() []any {
 	return []any{"kind", "Mock"}
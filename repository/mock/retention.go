@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/jmkng/zenin/internal"
+)
+
+// DeleteMeasurementBefore implements `RetentionRepository.DeleteMeasurementBefore` for `MockRepository`.
+func (m MockRepository) DeleteMeasurementBefore(ctx context.Context, id int, cutoff internal.TimeValue, limit int) (int, error) {
+	return 0, nil
+}
+
+// DeleteMeasurementBeyond implements `RetentionRepository.DeleteMeasurementBeyond` for `MockRepository`.
+func (m MockRepository) DeleteMeasurementBeyond(ctx context.Context, id int, keep int, limit int) (int, error) {
+	return 0, nil
+}
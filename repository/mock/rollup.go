@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/rollup"
+)
+
+// SelectRollup implements `RollupRepository.SelectRollup` for `MockRepository`.
+func (m MockRepository) SelectRollup(ctx context.Context, id int, resolution rollup.Resolution, params *rollup.SelectRollupParams) ([]rollup.Rollup, error) {
+	return []rollup.Rollup{}, nil
+}
+
+// SelectRollupLatest implements `RollupRepository.SelectRollupLatest` for `MockRepository`.
+func (m MockRepository) SelectRollupLatest(ctx context.Context, id int, resolution rollup.Resolution) (*internal.TimeValue, error) {
+	return nil, nil
+}
+
+// SelectRollupOrigin implements `RollupRepository.SelectRollupOrigin` for `MockRepository`.
+func (m MockRepository) SelectRollupOrigin(ctx context.Context, id int, from internal.TimeValue) (*internal.TimeValue, error) {
+	return nil, nil
+}
+
+// SelectRollupSample implements `RollupRepository.SelectRollupSample` for `MockRepository`.
+func (m MockRepository) SelectRollupSample(ctx context.Context, id int, from internal.TimeValue, to internal.TimeValue) ([]rollup.Sample, error) {
+	return []rollup.Sample{}, nil
+}
+
+// UpsertRollup implements `RollupRepository.UpsertRollup` for `MockRepository`.
+func (m MockRepository) UpsertRollup(ctx context.Context, rollups []rollup.Rollup) error {
+	return nil
+}
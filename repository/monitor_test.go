@@ -264,3 +264,38 @@ func fixture(t *testing.T) repository.Repository {
 func skip(t *testing.T) {
 	t.Skipf("environment variable %v not set", SkipKey)
 }
+
+func TestMonitorDependency(t *testing.T) {
+	if _, set := os.LookupEnv(SkipKey); !set {
+		skip(t)
+	}
+	repository := fixture(t)
+
+	ctx := context.Background()
+	id := 1
+	params := monitor.SelectMonitorParams{Id: &[]int{id}}
+	found, err := repository.SelectMonitor(ctx, 0, &params)
+	if err != nil {
+		t.Fatalf("failed to select monitor: %v", err)
+	}
+
+	found[0].ParentId = []int{3, 2}
+	if err := repository.UpdateMonitor(ctx, found[0]); err != nil {
+		t.Fatalf("failed to update monitor: %v", err)
+	}
+	found, err = repository.SelectMonitor(ctx, 0, &params)
+	if err != nil {
+		t.Fatalf("failed to select monitor: %v", err)
+	}
+	debug.AssertDeepEqual(t, found[0].ParentId, []int{2, 3})
+
+	// Deleting a parent removes the dependency.
+	if err := repository.DeleteMonitor(ctx, []int{2}); err != nil {
+		t.Fatalf("failed to delete monitor: %v", err)
+	}
+	found, err = repository.SelectMonitor(ctx, 0, &params)
+	if err != nil {
+		t.Fatalf("failed to select monitor: %v", err)
+	}
+	debug.AssertDeepEqual(t, found[0].ParentId, []int{3})
+}
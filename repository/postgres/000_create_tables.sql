@@ -15,14 +15,24 @@ CREATE TABLE account (
     root                  BOOLEAN NOT NULL DEFAULT false
 );
 
+CREATE TABLE credential (
+    created_at            TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    id                    SERIAL PRIMARY KEY,
+    name                  TEXT NOT NULL,
+    certificate           TEXT,
+    private_key           TEXT, -- Encrypted
+    ca_bundle             TEXT
+);
+
 CREATE TABLE monitor (
     created_at            TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     updated_at            TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     id                    SERIAL PRIMARY KEY,
     name                  TEXT NOT NULL,
-    kind                  TEXT NOT NULL CHECK (kind IN ('HTTP', 'TCP', 'ICMP', 'PLUGIN')),
+    kind                  TEXT NOT NULL CHECK (kind IN ('HTTP', 'TCP', 'UDP', 'ICMP', 'DNS', 'METRIC', 'PUSH', 'PLUGIN')),
     active                BOOLEAN NOT NULL,
-    "interval"            INTEGER NOT NULL CHECK ("interval" > 0), -- Seconds
+    "interval"            INTEGER NOT NULL CHECK ("interval" >= 0), -- Seconds, zero when "cron" is set
     timeout               INTEGER NOT NULL, -- Seconds
     description           TEXT,
     remote_address        TEXT,
@@ -41,7 +51,45 @@ CREATE TABLE monitor (
     icmp_count            INTEGER CHECK (icmp_count > 0),
     icmp_ttl              INTEGER CHECK (icmp_ttl > 0),
     icmp_protocol         TEXT CHECK (icmp_protocol IN ('ICMP', 'UDP')),
-    icmp_loss_threshold   INTEGER CHECK (icmp_loss_threshold < 100) -- Percentage (1 .. 99)
+    icmp_loss_threshold   INTEGER CHECK (icmp_loss_threshold < 100), -- Percentage (1 .. 99)
+    dns_record_type       TEXT CHECK (dns_record_type IN ('A', 'AAAA', 'CNAME', 'MX', 'TXT', 'NS', 'SRV', 'SOA')),
+    dns_resolver          TEXT,
+    dns_expected_answers  TEXT,
+    http_cert_expiry_days INTEGER CHECK (http_cert_expiry_days > 0), -- Days
+    http_assertions       TEXT,
+    http_follow_redirects BOOLEAN,
+    http_max_redirects    INTEGER CHECK (http_max_redirects >= 0),
+    http_skip_verify      BOOLEAN,
+    http_proxy            TEXT,
+    http_min_tls_version  TEXT CHECK (http_min_tls_version IN ('1.0', '1.1', '1.2', '1.3')),
+    http_version          TEXT CHECK (http_version IN ('HTTP/1.1', 'HTTP/2')),
+    credential_id         INTEGER REFERENCES credential(id) ON DELETE SET NULL,
+    tcp_send              TEXT,
+    tcp_expect            TEXT,
+    tcp_match             TEXT CHECK (tcp_match IN ('SUBSTRING', 'REGEX')),
+    tcp_read_limit        INTEGER CHECK (tcp_read_limit > 0), -- Bytes
+    tcp_tls               BOOLEAN,
+    tcp_server_name       TEXT,
+    tcp_skip_verify       BOOLEAN,
+    udp_send              TEXT,
+    udp_expect_reply      BOOLEAN,
+    udp_expect            TEXT,
+    udp_match             TEXT CHECK (udp_match IN ('SUBSTRING', 'REGEX')),
+    push_token            TEXT UNIQUE,
+    push_grace            INTEGER CHECK (push_grace >= 0), -- Seconds
+    cron                  TEXT,
+    cron_timezone         TEXT,
+    retry_count           INTEGER CHECK (retry_count >= 0),
+    retry_interval        INTEGER CHECK (retry_interval > 0), -- Seconds
+    flap_threshold        INTEGER CHECK (flap_threshold > 0),
+    flap_window           INTEGER CHECK (flap_window > 0), -- Seconds
+    retention_days        INTEGER CHECK (retention_days > 0),
+    retention_rows        INTEGER CHECK (retention_rows > 0),
+    metric_selector       TEXT,
+    metric_aggregate      TEXT CHECK (metric_aggregate IN ('SUM', 'MIN', 'MAX', 'AVG')),
+    metric_condition      TEXT CHECK (metric_condition IN ('ABOVE', 'BELOW')),
+    metric_warn           NUMERIC,
+    metric_dead           NUMERIC
 );
 
 CREATE TABLE event (
@@ -51,7 +99,8 @@ CREATE TABLE event (
     monitor_id            INTEGER REFERENCES "monitor"(id) ON DELETE CASCADE,
     plugin_name           TEXT NOT NULL,
     plugin_args           TEXT,
-    threshold             TEXT CHECK (threshold IN ('WARN', 'DEAD'))
+    threshold             TEXT CHECK (threshold IN ('WARN', 'DEAD', 'RECOVERED', 'FLAPPING')),
+    trigger_mode          TEXT CHECK (trigger_mode IN ('POLL', 'CHANGE'))
 );
 
 CREATE TABLE measurement (
@@ -59,9 +108,9 @@ CREATE TABLE measurement (
     updated_at            TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     id                    SERIAL PRIMARY KEY,
     monitor_id            INTEGER REFERENCES "monitor"(id) ON DELETE CASCADE,
-    state                 TEXT NOT NULL CHECK (state IN ('OK', 'WARN', 'DEAD')),
+    state                 TEXT NOT NULL CHECK (state IN ('OK', 'WARN', 'DEAD', 'UNREACHABLE')),
     state_hint            TEXT,
-    kind                  TEXT NOT NULL CHECK (kind IN ('HTTP', 'TCP', 'ICMP', 'PLUGIN')),
+    kind                  TEXT NOT NULL CHECK (kind IN ('HTTP', 'TCP', 'UDP', 'ICMP', 'DNS', 'METRIC', 'PUSH', 'PLUGIN')),
     duration              NUMERIC, -- Milliseconds
     http_status_code      INTEGER,
     http_response_headers TEXT,
@@ -73,7 +122,38 @@ CREATE TABLE measurement (
     icmp_max_rtt          NUMERIC,
     plugin_exit_code      INTEGER,
     plugin_stdout         TEXT,
-    plugin_stderr         TEXT
+    plugin_stderr         TEXT,
+    dns_answers           TEXT,
+    dns_rtt               NUMERIC, -- Milliseconds
+    http_dns_duration     NUMERIC, -- Milliseconds
+    http_connect_duration NUMERIC, -- Milliseconds
+    http_tls_duration     NUMERIC, -- Milliseconds
+    http_first_byte_duration NUMERIC, -- Milliseconds
+    http_transfer_duration NUMERIC, -- Milliseconds
+    http_redirects        TEXT,
+    retry_attempt         INTEGER, -- Set when the failure was not yet confirmed
+    flapping              BOOLEAN NOT NULL DEFAULT FALSE,
+    maintenance           BOOLEAN NOT NULL DEFAULT FALSE,
+    metric_value          NUMERIC
+);
+
+CREATE TABLE measurement_rollup (
+    monitor_id            INTEGER NOT NULL REFERENCES "monitor"(id) ON DELETE CASCADE,
+    resolution            TEXT NOT NULL CHECK (resolution IN ('HOUR', 'DAY')),
+    bucket                TIMESTAMPTZ NOT NULL, -- Start of the bucket
+    count_ok              INTEGER NOT NULL DEFAULT 0,
+    count_warn            INTEGER NOT NULL DEFAULT 0,
+    count_dead            INTEGER NOT NULL DEFAULT 0,
+    count_unreachable     INTEGER NOT NULL DEFAULT 0,
+    count_maintenance     INTEGER NOT NULL DEFAULT 0,
+    duration_min          NUMERIC NOT NULL DEFAULT 0, -- Milliseconds
+    duration_avg          NUMERIC NOT NULL DEFAULT 0, -- Milliseconds
+    duration_max          NUMERIC NOT NULL DEFAULT 0, -- Milliseconds
+    duration_p95          NUMERIC NOT NULL DEFAULT 0, -- Milliseconds
+    duration_p99          NUMERIC NOT NULL DEFAULT 0, -- Milliseconds
+    icmp_avg_rtt          NUMERIC,
+    icmp_loss             NUMERIC, -- Percentage
+    PRIMARY KEY (monitor_id, resolution, bucket)
 );
 
 CREATE TABLE certificate (
@@ -90,6 +170,32 @@ CREATE TABLE certificate (
   not_after            TIMESTAMPTZ
 );
 
+CREATE TABLE maintenance (
+    created_at            TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    id                    SERIAL PRIMARY KEY,
+    name                  TEXT NOT NULL,
+    description           TEXT,
+    mode                  TEXT NOT NULL CHECK (mode IN ('PAUSE', 'SUPPRESS')),
+    starts_at             TIMESTAMPTZ NOT NULL,
+    ends_at               TIMESTAMPTZ,
+    recurrence            TEXT, -- Cron expression or RRULE
+    timezone              TEXT,
+    duration              INTEGER CHECK (duration > 0) -- Seconds
+);
+
+CREATE TABLE maintenance_monitor (
+    maintenance_id        INTEGER NOT NULL REFERENCES maintenance(id) ON DELETE CASCADE,
+    monitor_id            INTEGER NOT NULL REFERENCES "monitor"(id) ON DELETE CASCADE,
+    PRIMARY KEY (maintenance_id, monitor_id)
+);
+
+CREATE TABLE monitor_dependency (
+    monitor_id            INTEGER NOT NULL REFERENCES "monitor"(id) ON DELETE CASCADE,
+    parent_id             INTEGER NOT NULL REFERENCES "monitor"(id) ON DELETE CASCADE,
+    PRIMARY KEY (monitor_id, parent_id)
+);
+
 CREATE OR REPLACE FUNCTION update_timestamp()
 RETURNS TRIGGER AS $$
 BEGIN
@@ -127,3 +233,8 @@ CREATE TRIGGER update_certificate_timestamp
 BEFORE UPDATE ON certificate
 FOR EACH ROW
 EXECUTE FUNCTION update_timestamp();
+
+CREATE TRIGGER update_maintenance_timestamp
+BEFORE UPDATE ON maintenance
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
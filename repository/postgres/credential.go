@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/repository/common"
+	"golang.org/x/net/context"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// SelectCredential implements `CredentialRepository.SelectCredential` for `PostgresRepository`.
+func (p PostgresRepository) SelectCredential(ctx context.Context, params *credential.SelectCredentialParams) ([]credential.Credential, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).SelectCredential(ctx, builder, params)
+}
+
+// InsertCredential implements `CredentialRepository.InsertCredential` for `PostgresRepository`.
+func (p PostgresRepository) InsertCredential(ctx context.Context, credential credential.Credential) (int, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	builder.Push(`INSERT INTO credential (created_at, updated_at, name, certificate, private_key, ca_bundle) VALUES (`)
+	builder.SpreadOpaque(credential.CreatedAt,
+		credential.UpdatedAt,
+		credential.Name,
+		credential.Certificate,
+		credential.PrivateKey,
+		credential.CABundle)
+	builder.Push(") RETURNING id")
+
+	var id int
+	err := p.db.QueryRowContext(ctx, builder.String(), builder.Args()...).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert credential: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateCredential implements `CredentialRepository.UpdateCredential` for `PostgresRepository`.
+func (p PostgresRepository) UpdateCredential(ctx context.Context, credential credential.Credential) error {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).UpdateCredential(ctx, builder, credential)
+}
+
+// DeleteCredential implements `CredentialRepository.DeleteCredential` for `PostgresRepository`.
+func (p PostgresRepository) DeleteCredential(ctx context.Context, id []int) error {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).DeleteCredential(ctx, builder, id)
+}
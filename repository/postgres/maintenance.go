@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmkng/zenin/internal/maintenance"
+	"github.com/jmkng/zenin/repository/common"
+	"golang.org/x/net/context"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// SelectMaintenance implements `MaintenanceRepository.SelectMaintenance` for `PostgresRepository`.
+func (p PostgresRepository) SelectMaintenance(ctx context.Context, params *maintenance.SelectMaintenanceParams) ([]maintenance.Maintenance, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).SelectMaintenance(ctx, builder, params)
+}
+
+// InsertMaintenance implements `MaintenanceRepository.InsertMaintenance` for `PostgresRepository`.
+func (p PostgresRepository) InsertMaintenance(ctx context.Context, maintenance maintenance.Maintenance) (int, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	builder.Push(`INSERT INTO maintenance (created_at, updated_at, name, description, mode, starts_at, ends_at, recurrence, timezone, duration) VALUES (`)
+	builder.SpreadOpaque(maintenance.CreatedAt,
+		maintenance.UpdatedAt,
+		maintenance.Name,
+		maintenance.Description,
+		maintenance.Mode,
+		maintenance.StartsAt,
+		maintenance.EndsAt,
+		maintenance.Recurrence,
+		maintenance.Timezone,
+		maintenance.Duration)
+	builder.Push(") RETURNING id")
+
+	var id int
+	if err := tx.QueryRowContext(ctx, builder.String(), builder.Args()...).Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to insert maintenance: %w", err)
+	}
+
+	builder = zsql.NewBuilder(zsql.NumberPositional)
+	if err := common.NewCommonRepository(p.db).ReplaceMaintenanceMonitors(ctx, tx, builder, id, maintenance.MonitorId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// UpdateMaintenance implements `MaintenanceRepository.UpdateMaintenance` for `PostgresRepository`.
+func (p PostgresRepository) UpdateMaintenance(ctx context.Context, maintenance maintenance.Maintenance) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	builder.Push("UPDATE maintenance SET updated_at = ")
+	builder.BindOpaque(maintenance.UpdatedAt)
+	builder.Push(", name = ")
+	builder.BindString(maintenance.Name)
+	builder.Push(", description = ")
+	builder.BindOpaque(maintenance.Description)
+	builder.Push(", mode = ")
+	builder.BindOpaque(maintenance.Mode)
+	builder.Push(", starts_at = ")
+	builder.BindOpaque(maintenance.StartsAt)
+	builder.Push(", ends_at = ")
+	builder.BindOpaque(maintenance.EndsAt)
+	builder.Push(", recurrence = ")
+	builder.BindOpaque(maintenance.Recurrence)
+	builder.Push(", timezone = ")
+	builder.BindOpaque(maintenance.Timezone)
+	builder.Push(", duration = ")
+	builder.BindOpaque(maintenance.Duration)
+	builder.Push(" WHERE id = ")
+	builder.BindInt(*maintenance.Id)
+	if _, err := tx.ExecContext(ctx, builder.String(), builder.Args()...); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update maintenance: %w", err)
+	}
+
+	builder = zsql.NewBuilder(zsql.NumberPositional)
+	if err := common.NewCommonRepository(p.db).ReplaceMaintenanceMonitors(ctx, tx, builder, *maintenance.Id, maintenance.MonitorId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteMaintenance implements `MaintenanceRepository.DeleteMaintenance` for `PostgresRepository`.
+func (p PostgresRepository) DeleteMaintenance(ctx context.Context, id []int) error {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).DeleteMaintenance(ctx, builder, id)
+}
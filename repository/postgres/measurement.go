@@ -28,9 +28,21 @@ func (p PostgresRepository) InsertMeasurement(ctx context.Context, measurement m
 		icmp_max_rtt,
         plugin_exit_code,
 		plugin_stdout,
-		plugin_stderr)
+		plugin_stderr,
+		dns_answers,
+		dns_rtt,
+		http_dns_duration,
+		http_connect_duration,
+		http_tls_duration,
+		http_first_byte_duration,
+		http_transfer_duration,
+		http_redirects,
+		retry_attempt,
+		flapping,
+		maintenance,
+		metric_value)
     VALUES
-        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)
     RETURNING id`
 	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -55,6 +67,18 @@ func (p PostgresRepository) InsertMeasurement(ctx context.Context, measurement m
 		measurement.PluginExitCode,
 		measurement.PluginStdout,
 		measurement.PluginStderr,
+		measurement.DNSAnswers,
+		measurement.DNSRTT,
+		measurement.HTTPDNSDuration,
+		measurement.HTTPConnectDuration,
+		measurement.HTTPTLSDuration,
+		measurement.HTTPFirstByteDuration,
+		measurement.HTTPTransferDuration,
+		measurement.HTTPRedirects,
+		measurement.RetryAttempt,
+		measurement.Flapping,
+		measurement.Maintenance,
+		measurement.MetricValue,
 	)
 	var id int
 	err = row.Scan(&id)
@@ -112,3 +136,9 @@ func (p PostgresRepository) DeleteMeasurement(ctx context.Context, id []int) err
 	builder := zsql.NewBuilder(zsql.NumberPositional)
 	return common.NewCommonRepository(p.db).DeleteMeasurement(ctx, builder, id)
 }
+
+// SelectLastState implements `MeasurementRepository.SelectLastState` for `PostgresRepository`.
+func (p PostgresRepository) SelectLastState(ctx context.Context, id int) (*measurement.ProbeState, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).SelectLastState(ctx, builder, id)
+}
@@ -45,9 +45,47 @@ func (p PostgresRepository) InsertMonitor(ctx context.Context, monitor monitor.M
 		icmp_count,
 		icmp_ttl,
 		icmp_protocol,
-        icmp_loss_threshold)
+        icmp_loss_threshold,
+		dns_record_type,
+		dns_resolver,
+		dns_expected_answers,
+		http_cert_expiry_days,
+		http_assertions,
+		http_follow_redirects,
+		http_max_redirects,
+		http_skip_verify,
+		http_proxy,
+		http_min_tls_version,
+		http_version,
+		credential_id,
+		tcp_send,
+		tcp_expect,
+		tcp_match,
+		tcp_read_limit,
+		tcp_tls,
+		tcp_server_name,
+		tcp_skip_verify,
+		udp_send,
+		udp_expect_reply,
+		udp_expect,
+		udp_match,
+		push_token,
+		push_grace,
+		cron,
+		cron_timezone,
+		retry_count,
+		retry_interval,
+		flap_threshold,
+		flap_window,
+		retention_days,
+		retention_rows,
+		metric_selector,
+		metric_aggregate,
+		metric_condition,
+		metric_warn,
+		metric_dead)
     VALUES 
-        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46, $47, $48, $49, $50, $51, $52, $53, $54, $55, $56, $57, $58, $59, $60, $61, $62, $63)
     RETURNING id`
 	row := tx.QueryRowContext(ctx, query,
 		monitor.Name,
@@ -74,7 +112,45 @@ func (p PostgresRepository) InsertMonitor(ctx context.Context, monitor monitor.M
 		monitor.ICMPCount,
 		monitor.ICMPTTL,
 		monitor.ICMPProtocol,
-		monitor.ICMPLossThreshold)
+		monitor.ICMPLossThreshold,
+		monitor.DNSRecordType,
+		monitor.DNSResolver,
+		monitor.DNSExpectedAnswers,
+		monitor.HTTPCertExpiryDays,
+		monitor.HTTPAssertions,
+		monitor.HTTPFollowRedirects,
+		monitor.HTTPMaxRedirects,
+		monitor.HTTPSkipVerify,
+		monitor.HTTPProxy,
+		monitor.HTTPMinTLSVersion,
+		monitor.HTTPVersion,
+		monitor.CredentialId,
+		monitor.TCPSend,
+		monitor.TCPExpect,
+		monitor.TCPMatch,
+		monitor.TCPReadLimit,
+		monitor.TCPTLS,
+		monitor.TCPServerName,
+		monitor.TCPSkipVerify,
+		monitor.UDPSend,
+		monitor.UDPExpectReply,
+		monitor.UDPExpect,
+		monitor.UDPMatch,
+		monitor.PushToken,
+		monitor.PushGrace,
+		monitor.Cron,
+		monitor.CronTimezone,
+		monitor.RetryCount,
+		monitor.RetryInterval,
+		monitor.FlapThreshold,
+		monitor.FlapWindow,
+		monitor.RetentionDays,
+		monitor.RetentionRows,
+		monitor.MetricSelector,
+		monitor.MetricAggregate,
+		monitor.MetricCondition,
+		monitor.MetricWarn,
+		monitor.MetricDead)
 	if err = row.Scan(&id); err != nil {
 		tx.Rollback()
 		return 0, err
@@ -87,6 +163,12 @@ func (p PostgresRepository) InsertMonitor(ctx context.Context, monitor monitor.M
 		}
 	}
 
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	if err = common.NewCommonRepository(p.db).ReplaceMonitorParents(ctx, tx, builder, id, monitor.ParentId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
 	return id, tx.Commit()
 }
 
@@ -137,8 +219,46 @@ func (p PostgresRepository) UpdateMonitor(ctx context.Context, monitor monitor.M
 		icmp_count = $21,
 		icmp_ttl = $22,
 		icmp_protocol = $23,
-        icmp_loss_threshold = $24
-    WHERE id = $25`
+        icmp_loss_threshold = $24,
+		dns_record_type = $25,
+		dns_resolver = $26,
+		dns_expected_answers = $27,
+		http_cert_expiry_days = $28,
+		http_assertions = $29,
+		http_follow_redirects = $30,
+		http_max_redirects = $31,
+		http_skip_verify = $32,
+		http_proxy = $33,
+		http_min_tls_version = $34,
+		http_version = $35,
+		credential_id = $36,
+		tcp_send = $37,
+		tcp_expect = $38,
+		tcp_match = $39,
+		tcp_read_limit = $40,
+		tcp_tls = $41,
+		tcp_server_name = $42,
+		tcp_skip_verify = $43,
+		udp_send = $44,
+		udp_expect_reply = $45,
+		udp_expect = $46,
+		udp_match = $47,
+		push_token = $48,
+		push_grace = $49,
+		cron = $50,
+		cron_timezone = $51,
+		retry_count = $52,
+		retry_interval = $53,
+		flap_threshold = $54,
+		flap_window = $55,
+		retention_days = $56,
+		retention_rows = $57,
+		metric_selector = $58,
+		metric_aggregate = $59,
+		metric_condition = $60,
+		metric_warn = $61,
+		metric_dead = $62
+    WHERE id = $63`
 	if _, err = tx.ExecContext(ctx, q2,
 		monitor.Name,
 		monitor.UpdatedAt,
@@ -164,6 +284,44 @@ func (p PostgresRepository) UpdateMonitor(ctx context.Context, monitor monitor.M
 		monitor.ICMPTTL,
 		monitor.ICMPProtocol,
 		monitor.ICMPLossThreshold,
+		monitor.DNSRecordType,
+		monitor.DNSResolver,
+		monitor.DNSExpectedAnswers,
+		monitor.HTTPCertExpiryDays,
+		monitor.HTTPAssertions,
+		monitor.HTTPFollowRedirects,
+		monitor.HTTPMaxRedirects,
+		monitor.HTTPSkipVerify,
+		monitor.HTTPProxy,
+		monitor.HTTPMinTLSVersion,
+		monitor.HTTPVersion,
+		monitor.CredentialId,
+		monitor.TCPSend,
+		monitor.TCPExpect,
+		monitor.TCPMatch,
+		monitor.TCPReadLimit,
+		monitor.TCPTLS,
+		monitor.TCPServerName,
+		monitor.TCPSkipVerify,
+		monitor.UDPSend,
+		monitor.UDPExpectReply,
+		monitor.UDPExpect,
+		monitor.UDPMatch,
+		monitor.PushToken,
+		monitor.PushGrace,
+		monitor.Cron,
+		monitor.CronTimezone,
+		monitor.RetryCount,
+		monitor.RetryInterval,
+		monitor.FlapThreshold,
+		monitor.FlapWindow,
+		monitor.RetentionDays,
+		monitor.RetentionRows,
+		monitor.MetricSelector,
+		monitor.MetricAggregate,
+		monitor.MetricCondition,
+		monitor.MetricWarn,
+		monitor.MetricDead,
 		monitor.Id); err != nil {
 		tx.Rollback()
 		return err
@@ -176,6 +334,12 @@ func (p PostgresRepository) UpdateMonitor(ctx context.Context, monitor monitor.M
 		}
 	}
 
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	if err := common.NewCommonRepository(p.db).ReplaceMonitorParents(ctx, tx, builder, *monitor.Id, monitor.ParentId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -200,10 +364,10 @@ func (p PostgresRepository) ToggleMonitor(ctx context.Context, id []int, active
 
 func (p PostgresRepository) insertEvents(ctx context.Context, tx *sql.Tx, id int, e []monitor.Event) error {
 	const q3 string = `INSERT INTO event 
-        (monitor_id, plugin_name, plugin_args, threshold)
-        VALUES ($1, $2, $3, $4)`
+        (monitor_id, plugin_name, plugin_args, threshold, trigger_mode)
+        VALUES ($1, $2, $3, $4, $5)`
 	for _, v := range e {
-		if _, err := tx.ExecContext(ctx, q3, id, v.PluginName, v.PluginArgs, v.Threshold); err != nil {
+		if _, err := tx.ExecContext(ctx, q3, id, v.PluginName, v.PluginArgs, v.Threshold, v.Trigger); err != nil {
 			return err
 		}
 	}
@@ -80,6 +80,11 @@ func (p PostgresRepository) Fixture() error {
 	return nil
 }
 
+// Close implements `Repository.Close` for `PostgresRepository`.
+func (p PostgresRepository) Close() error {
+	return p.db.Close()
+}
+
 // Describe implements `Repository.Describe` for `PostgresRepository`.
 func (p PostgresRepository) Describe() []any {
 	return []any{"kind", "PostgreSQL", "address", env.Env.Repository.Host, "port", env.Env.Repository.Port}
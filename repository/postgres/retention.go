@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/repository/common"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// DeleteMeasurementBefore implements `RetentionRepository.DeleteMeasurementBefore` for `PostgresRepository`.
+func (p PostgresRepository) DeleteMeasurementBefore(ctx context.Context, id int, cutoff internal.TimeValue, limit int) (int, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).DeleteMeasurementBefore(ctx, builder, id, cutoff, limit)
+}
+
+// DeleteMeasurementBeyond implements `RetentionRepository.DeleteMeasurementBeyond` for `PostgresRepository`.
+func (p PostgresRepository) DeleteMeasurementBeyond(ctx context.Context, id int, keep int, limit int) (int, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).DeleteMeasurementBeyond(ctx, builder, id, keep, limit)
+}
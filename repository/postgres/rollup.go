@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/rollup"
+	"github.com/jmkng/zenin/repository/common"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// SelectRollup implements `RollupRepository.SelectRollup` for `PostgresRepository`.
+func (p PostgresRepository) SelectRollup(ctx context.Context, id int, resolution rollup.Resolution, params *rollup.SelectRollupParams) ([]rollup.Rollup, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).SelectRollup(ctx, builder, id, resolution, params)
+}
+
+// SelectRollupLatest implements `RollupRepository.SelectRollupLatest` for `PostgresRepository`.
+func (p PostgresRepository) SelectRollupLatest(ctx context.Context, id int, resolution rollup.Resolution) (*internal.TimeValue, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).SelectRollupLatest(ctx, builder, id, resolution)
+}
+
+// SelectRollupOrigin implements `RollupRepository.SelectRollupOrigin` for `PostgresRepository`.
+func (p PostgresRepository) SelectRollupOrigin(ctx context.Context, id int, from internal.TimeValue) (*internal.TimeValue, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).SelectRollupOrigin(ctx, builder, id, from)
+}
+
+// SelectRollupSample implements `RollupRepository.SelectRollupSample` for `PostgresRepository`.
+func (p PostgresRepository) SelectRollupSample(ctx context.Context, id int, from internal.TimeValue, to internal.TimeValue) ([]rollup.Sample, error) {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).SelectRollupSample(ctx, builder, id, from, to)
+}
+
+// UpsertRollup implements `RollupRepository.UpsertRollup` for `PostgresRepository`.
+func (p PostgresRepository) UpsertRollup(ctx context.Context, rollups []rollup.Rollup) error {
+	builder := zsql.NewBuilder(zsql.NumberPositional)
+	return common.NewCommonRepository(p.db).UpsertRollup(ctx, builder, rollups)
+}
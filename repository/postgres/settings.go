@@ -12,14 +12,16 @@ import (
 func (p PostgresRepository) UpdateSettings(ctx context.Context, s settings.Settings) error {
 	// https://www.postgresql.org/docs/current/sql-insert.html#id-1.9.3.152.6.3.3
 	query := `INSERT INTO settings ("key", text_value)
-	VALUES ($1, $2), ($3, $4)
+	VALUES ($1, $2), ($3, $4), ($5, $6), ($7, $8)
 	ON CONFLICT ("key")
 	DO UPDATE SET
 		text_value = EXCLUDED.text_value`
 
 	_, err := p.db.ExecContext(ctx, query,
 		settings.DelimitersKey, s.Delimiters,
-		settings.ThemeKey, s.Theme)
+		settings.ThemeKey, s.Theme,
+		settings.RetentionDaysKey, settings.IntText(s.RetentionDays),
+		settings.RetentionRowsKey, settings.IntText(s.RetentionRows))
 
 	if err != nil {
 		return fmt.Errorf("failed to update settings: %w", err)
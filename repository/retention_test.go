@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+)
+
+func TestRetention(t *testing.T) {
+	if _, set := os.LookupEnv(SkipKey); !set {
+		skip(t)
+	}
+	repository := fixture(t)
+
+	ctx := context.Background()
+	mid := 4
+	before, err := repository.SelectMeasurement(ctx, mid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		_, err := repository.InsertMeasurement(ctx, measurement.Measurement{
+			MonitorId: &mid,
+			Span:      measurement.Span{State: measurement.Ok, Kind: measurement.HTTP},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	total := len(before) + 3
+
+	// Nothing has expired yet.
+	deleted, err := repository.DeleteMeasurementBefore(ctx, mid, internal.NewTimeValue(time.Now().Add(-time.Hour)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, deleted, 0)
+
+	// Only the newest rows are kept.
+	deleted, err = repository.DeleteMeasurementBeyond(ctx, mid, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, deleted, total-2)
+
+	after, err := repository.SelectMeasurement(ctx, mid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(after), 2)
+
+	// Expired rows are deleted in batches.
+	cutoff := internal.NewTimeValue(time.Now().Add(time.Hour))
+	for _, expect := range []int{1, 1, 0} {
+		deleted, err = repository.DeleteMeasurementBefore(ctx, mid, cutoff, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		debug.AssertEqual(t, deleted, expect)
+	}
+}
+
+func TestRetentionCertificate(t *testing.T) {
+	if _, set := os.LookupEnv(SkipKey); !set {
+		skip(t)
+	}
+	repository := fixture(t)
+
+	// Measurement 4 of monitor 2 has certificates in the fixture.
+	ctx := context.Background()
+	before, err := repository.SelectCertificate(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(before), 3)
+
+	if _, err := repository.DeleteMeasurementBeyond(ctx, 2, 0, 100); err != nil {
+		t.Fatal(err)
+	}
+	after, err := repository.SelectCertificate(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(after), 0)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/rollup"
+)
+
+func TestRollup(t *testing.T) {
+	if _, set := os.LookupEnv(SkipKey); !set {
+		skip(t)
+	}
+	repository := fixture(t)
+
+	ctx := context.Background()
+	mid := 4
+	for _, v := range []float64{10, 20, 30} {
+		_, err := repository.InsertMeasurement(ctx, measurement.Measurement{
+			MonitorId: &mid,
+			Duration:  v,
+			Span:      measurement.Span{State: measurement.Ok, Kind: measurement.HTTP},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	measurements, err := repository.SelectMeasurement(ctx, mid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := 0
+	for _, v := range measurements {
+		if v.RetryAttempt == nil && !v.Maintenance {
+			expect++
+		}
+	}
+
+	// Nothing is aggregated until the bucket is complete.
+	service := rollup.NewRollupService(repository, repository)
+	if err := service.Aggregate(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	daily, err := service.GetRollups(ctx, mid, rollup.Day, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(daily), 0)
+
+	later := time.Now().Add(48 * time.Hour)
+	for range 2 {
+		if err := service.Aggregate(ctx, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, resolution := range []rollup.Resolution{rollup.Hour, rollup.Day} {
+		rollups, err := service.GetRollups(ctx, mid, resolution, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, v := range rollups {
+			count += v.Count()
+			debug.AssertEqual(t, v.Resolution, resolution)
+			debug.Assert(t, v.DurationMax >= 30, "expected duration maximum of inserted measurements")
+		}
+		debug.AssertEqual(t, count, expect)
+	}
+
+	latest, err := repository.SelectRollupLatest(ctx, mid, rollup.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.Assert(t, latest != nil, "expected latest hourly bucket")
+	after := *latest
+	rollups, err := service.GetRollups(ctx, mid, rollup.Hour, &rollup.SelectRollupParams{After: &after})
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, len(rollups), 1)
+}
@@ -55,6 +55,23 @@ func TestUpdateSettings(t *testing.T) {
 	debug.AssertEqual(t, (*after.Delimiters)[0], open)
 	debug.AssertEqual(t, (*after.Delimiters)[1], close)
 	debug.AssertEqual(t, *after.Theme, theme)
+	debug.AssertEqual(t, after.RetentionDays, nil)
+
+	days, rows := 30, 1000
+	err = repository.UpdateSettings(ctx, settings.Settings{
+		Delimiters:    &delimiters,
+		RetentionDays: &days,
+		RetentionRows: &rows,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	retained, err := repository.SelectSettings(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertDeepEqual(t, retained.RetentionDays, &days)
+	debug.AssertDeepEqual(t, retained.RetentionRows, &rows)
 
 	err = repository.UpdateSettings(ctx, settings.Settings{
 		Delimiters: nil,
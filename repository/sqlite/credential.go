@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/repository/common"
+	"golang.org/x/net/context"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// SelectCredential implements `CredentialRepository.SelectCredential` for `SQLiteRepository`.
+func (s SQLiteRepository) SelectCredential(ctx context.Context, params *credential.SelectCredentialParams) ([]credential.Credential, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).SelectCredential(ctx, builder, params)
+}
+
+// InsertCredential implements `CredentialRepository.InsertCredential` for `SQLiteRepository`.
+func (s SQLiteRepository) InsertCredential(ctx context.Context, credential credential.Credential) (int, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	builder.Push(`INSERT INTO credential (created_at, updated_at, name, certificate, private_key, ca_bundle) VALUES (`)
+	builder.SpreadOpaque(credential.CreatedAt,
+		credential.UpdatedAt,
+		credential.Name,
+		credential.Certificate,
+		credential.PrivateKey,
+		credential.CABundle)
+	builder.Push(")")
+
+	result, err := s.db.ExecContext(ctx, builder.String(), builder.Args()...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert credential: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get insert id: %w", err)
+	}
+
+	return int(id), nil
+}
+
+// UpdateCredential implements `CredentialRepository.UpdateCredential` for `SQLiteRepository`.
+func (s SQLiteRepository) UpdateCredential(ctx context.Context, credential credential.Credential) error {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).UpdateCredential(ctx, builder, credential)
+}
+
+// DeleteCredential implements `CredentialRepository.DeleteCredential` for `SQLiteRepository`.
+func (s SQLiteRepository) DeleteCredential(ctx context.Context, id []int) error {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).DeleteCredential(ctx, builder, id)
+}
@@ -28,9 +28,21 @@ func (s SQLiteRepository) InsertMeasurement(ctx context.Context, measurement mea
 		icmp_max_rtt,
         plugin_exit_code,
 		plugin_stdout,
-		plugin_stderr)
+		plugin_stderr,
+		dns_answers,
+		dns_rtt,
+		http_dns_duration,
+		http_connect_duration,
+		http_tls_duration,
+		http_first_byte_duration,
+		http_transfer_duration,
+		http_redirects,
+		retry_attempt,
+		flapping,
+		maintenance,
+		metric_value)
     VALUES
-        (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+        (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return -1, err
@@ -54,6 +66,18 @@ func (s SQLiteRepository) InsertMeasurement(ctx context.Context, measurement mea
 		measurement.PluginExitCode,
 		measurement.PluginStdout,
 		measurement.PluginStderr,
+		measurement.DNSAnswers,
+		measurement.DNSRTT,
+		measurement.HTTPDNSDuration,
+		measurement.HTTPConnectDuration,
+		measurement.HTTPTLSDuration,
+		measurement.HTTPFirstByteDuration,
+		measurement.HTTPTransferDuration,
+		measurement.HTTPRedirects,
+		measurement.RetryAttempt,
+		measurement.Flapping,
+		measurement.Maintenance,
+		measurement.MetricValue,
 	)
 	if err != nil {
 		return -1, errors.Join(err, tx.Rollback())
@@ -115,3 +139,9 @@ func (s SQLiteRepository) DeleteMeasurement(ctx context.Context, id []int) error
 	builder := zsql.NewBuilder(zsql.QuestionPositional)
 	return common.NewCommonRepository(s.db).DeleteMeasurement(ctx, builder, id)
 }
+
+// SelectLastState implements `MeasurementRepository.SelectLastState` for `SQLiteRepository`.
+func (s SQLiteRepository) SelectLastState(ctx context.Context, id int) (*measurement.ProbeState, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).SelectLastState(ctx, builder, id)
+}
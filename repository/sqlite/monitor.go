@@ -45,9 +45,47 @@ func (s SQLiteRepository) InsertMonitor(ctx context.Context, monitor monitor.Mon
 		icmp_count,
 		icmp_ttl,
 		icmp_protocol,
-        icmp_loss_threshold)
+        icmp_loss_threshold,
+		dns_record_type,
+		dns_resolver,
+		dns_expected_answers,
+		http_cert_expiry_days,
+		http_assertions,
+		http_follow_redirects,
+		http_max_redirects,
+		http_skip_verify,
+		http_proxy,
+		http_min_tls_version,
+		http_version,
+		credential_id,
+		tcp_send,
+		tcp_expect,
+		tcp_match,
+		tcp_read_limit,
+		tcp_tls,
+		tcp_server_name,
+		tcp_skip_verify,
+		udp_send,
+		udp_expect_reply,
+		udp_expect,
+		udp_match,
+		push_token,
+		push_grace,
+		cron,
+		cron_timezone,
+		retry_count,
+		retry_interval,
+		flap_threshold,
+		flap_window,
+		retention_days,
+		retention_rows,
+		metric_selector,
+		metric_aggregate,
+		metric_condition,
+		metric_warn,
+		metric_dead)
     VALUES 
-        (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+        (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	result, err := tx.ExecContext(ctx, query,
 		monitor.Name,
 		monitor.CreatedAt,
@@ -73,7 +111,45 @@ func (s SQLiteRepository) InsertMonitor(ctx context.Context, monitor monitor.Mon
 		monitor.ICMPCount,
 		monitor.ICMPTTL,
 		monitor.ICMPProtocol,
-		monitor.ICMPLossThreshold)
+		monitor.ICMPLossThreshold,
+		monitor.DNSRecordType,
+		monitor.DNSResolver,
+		monitor.DNSExpectedAnswers,
+		monitor.HTTPCertExpiryDays,
+		monitor.HTTPAssertions,
+		monitor.HTTPFollowRedirects,
+		monitor.HTTPMaxRedirects,
+		monitor.HTTPSkipVerify,
+		monitor.HTTPProxy,
+		monitor.HTTPMinTLSVersion,
+		monitor.HTTPVersion,
+		monitor.CredentialId,
+		monitor.TCPSend,
+		monitor.TCPExpect,
+		monitor.TCPMatch,
+		monitor.TCPReadLimit,
+		monitor.TCPTLS,
+		monitor.TCPServerName,
+		monitor.TCPSkipVerify,
+		monitor.UDPSend,
+		monitor.UDPExpectReply,
+		monitor.UDPExpect,
+		monitor.UDPMatch,
+		monitor.PushToken,
+		monitor.PushGrace,
+		monitor.Cron,
+		monitor.CronTimezone,
+		monitor.RetryCount,
+		monitor.RetryInterval,
+		monitor.FlapThreshold,
+		monitor.FlapWindow,
+		monitor.RetentionDays,
+		monitor.RetentionRows,
+		monitor.MetricSelector,
+		monitor.MetricAggregate,
+		monitor.MetricCondition,
+		monitor.MetricWarn,
+		monitor.MetricDead)
 	if err != nil {
 		tx.Rollback()
 		return 0, err
@@ -92,6 +168,12 @@ func (s SQLiteRepository) InsertMonitor(ctx context.Context, monitor monitor.Mon
 		}
 	}
 
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	if err = common.NewCommonRepository(s.db).ReplaceMonitorParents(ctx, tx, builder, id, monitor.ParentId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
 	return id, tx.Commit()
 }
 
@@ -142,7 +224,45 @@ func (s SQLiteRepository) UpdateMonitor(ctx context.Context, monitor monitor.Mon
 		icmp_count = ?,
 		icmp_ttl = ?,
 		icmp_protocol = ?,
-        icmp_loss_threshold = ?
+        icmp_loss_threshold = ?,
+		dns_record_type = ?,
+		dns_resolver = ?,
+		dns_expected_answers = ?,
+		http_cert_expiry_days = ?,
+		http_assertions = ?,
+		http_follow_redirects = ?,
+		http_max_redirects = ?,
+		http_skip_verify = ?,
+		http_proxy = ?,
+		http_min_tls_version = ?,
+		http_version = ?,
+		credential_id = ?,
+		tcp_send = ?,
+		tcp_expect = ?,
+		tcp_match = ?,
+		tcp_read_limit = ?,
+		tcp_tls = ?,
+		tcp_server_name = ?,
+		tcp_skip_verify = ?,
+		udp_send = ?,
+		udp_expect_reply = ?,
+		udp_expect = ?,
+		udp_match = ?,
+		push_token = ?,
+		push_grace = ?,
+		cron = ?,
+		cron_timezone = ?,
+		retry_count = ?,
+		retry_interval = ?,
+		flap_threshold = ?,
+		flap_window = ?,
+		retention_days = ?,
+		retention_rows = ?,
+		metric_selector = ?,
+		metric_aggregate = ?,
+		metric_condition = ?,
+		metric_warn = ?,
+		metric_dead = ?
     WHERE id = ?`
 	if _, err = tx.ExecContext(ctx, q2,
 		monitor.Name,
@@ -169,6 +289,44 @@ func (s SQLiteRepository) UpdateMonitor(ctx context.Context, monitor monitor.Mon
 		monitor.ICMPTTL,
 		monitor.ICMPProtocol,
 		monitor.ICMPLossThreshold,
+		monitor.DNSRecordType,
+		monitor.DNSResolver,
+		monitor.DNSExpectedAnswers,
+		monitor.HTTPCertExpiryDays,
+		monitor.HTTPAssertions,
+		monitor.HTTPFollowRedirects,
+		monitor.HTTPMaxRedirects,
+		monitor.HTTPSkipVerify,
+		monitor.HTTPProxy,
+		monitor.HTTPMinTLSVersion,
+		monitor.HTTPVersion,
+		monitor.CredentialId,
+		monitor.TCPSend,
+		monitor.TCPExpect,
+		monitor.TCPMatch,
+		monitor.TCPReadLimit,
+		monitor.TCPTLS,
+		monitor.TCPServerName,
+		monitor.TCPSkipVerify,
+		monitor.UDPSend,
+		monitor.UDPExpectReply,
+		monitor.UDPExpect,
+		monitor.UDPMatch,
+		monitor.PushToken,
+		monitor.PushGrace,
+		monitor.Cron,
+		monitor.CronTimezone,
+		monitor.RetryCount,
+		monitor.RetryInterval,
+		monitor.FlapThreshold,
+		monitor.FlapWindow,
+		monitor.RetentionDays,
+		monitor.RetentionRows,
+		monitor.MetricSelector,
+		monitor.MetricAggregate,
+		monitor.MetricCondition,
+		monitor.MetricWarn,
+		monitor.MetricDead,
 		monitor.Id); err != nil {
 		tx.Rollback()
 		return err
@@ -181,6 +339,12 @@ func (s SQLiteRepository) UpdateMonitor(ctx context.Context, monitor monitor.Mon
 		}
 	}
 
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	if err := common.NewCommonRepository(s.db).ReplaceMonitorParents(ctx, tx, builder, *monitor.Id, monitor.ParentId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -204,10 +368,10 @@ func (s SQLiteRepository) ToggleMonitor(ctx context.Context, id []int, active bo
 
 func (s SQLiteRepository) insertEvents(ctx context.Context, tx *sql.Tx, id int, e []monitor.Event) error {
 	const q3 string = `INSERT INTO event 
-        (monitor_id, plugin_name, plugin_args, threshold)
-        VALUES (?, ?, ?, ?)`
+        (monitor_id, plugin_name, plugin_args, threshold, trigger_mode)
+        VALUES (?, ?, ?, ?, ?)`
 	for _, v := range e {
-		if _, err := tx.ExecContext(ctx, q3, id, v.PluginName, v.PluginArgs, v.Threshold); err != nil {
+		if _, err := tx.ExecContext(ctx, q3, id, v.PluginName, v.PluginArgs, v.Threshold, v.Trigger); err != nil {
 			return err
 		}
 	}
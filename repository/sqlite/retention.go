@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/repository/common"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// DeleteMeasurementBefore implements `RetentionRepository.DeleteMeasurementBefore` for `SQLiteRepository`.
+func (s SQLiteRepository) DeleteMeasurementBefore(ctx context.Context, id int, cutoff internal.TimeValue, limit int) (int, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	// The column default is CURRENT_TIMESTAMP, so compare in the same format.
+	text := cutoff.Time().Format(time.DateTime)
+	return common.NewCommonRepository(s.db).DeleteMeasurementBefore(ctx, builder, id, text, limit)
+}
+
+// DeleteMeasurementBeyond implements `RetentionRepository.DeleteMeasurementBeyond` for `SQLiteRepository`.
+func (s SQLiteRepository) DeleteMeasurementBeyond(ctx context.Context, id int, keep int, limit int) (int, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).DeleteMeasurementBeyond(ctx, builder, id, keep, limit)
+}
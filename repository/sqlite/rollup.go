@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/rollup"
+	"github.com/jmkng/zenin/repository/common"
+
+	zsql "github.com/jmkng/zenin/pkg/sql"
+)
+
+// SelectRollup implements `RollupRepository.SelectRollup` for `SQLiteRepository`.
+func (s SQLiteRepository) SelectRollup(ctx context.Context, id int, resolution rollup.Resolution, params *rollup.SelectRollupParams) ([]rollup.Rollup, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).SelectRollup(ctx, builder, id, resolution, params)
+}
+
+// SelectRollupLatest implements `RollupRepository.SelectRollupLatest` for `SQLiteRepository`.
+func (s SQLiteRepository) SelectRollupLatest(ctx context.Context, id int, resolution rollup.Resolution) (*internal.TimeValue, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).SelectRollupLatest(ctx, builder, id, resolution)
+}
+
+// SelectRollupOrigin implements `RollupRepository.SelectRollupOrigin` for `SQLiteRepository`.
+func (s SQLiteRepository) SelectRollupOrigin(ctx context.Context, id int, from internal.TimeValue) (*internal.TimeValue, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	// The column default is CURRENT_TIMESTAMP, so compare in the same format.
+	text := from.Time().Format(time.DateTime)
+	return common.NewCommonRepository(s.db).SelectRollupOrigin(ctx, builder, id, text)
+}
+
+// SelectRollupSample implements `RollupRepository.SelectRollupSample` for `SQLiteRepository`.
+func (s SQLiteRepository) SelectRollupSample(ctx context.Context, id int, from internal.TimeValue, to internal.TimeValue) ([]rollup.Sample, error) {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).SelectRollupSample(ctx, builder, id,
+		from.Time().Format(time.DateTime), to.Time().Format(time.DateTime))
+}
+
+// UpsertRollup implements `RollupRepository.UpsertRollup` for `SQLiteRepository`.
+func (s SQLiteRepository) UpsertRollup(ctx context.Context, rollups []rollup.Rollup) error {
+	builder := zsql.NewBuilder(zsql.QuestionPositional)
+	return common.NewCommonRepository(s.db).UpsertRollup(ctx, builder, rollups)
+}
@@ -14,7 +14,7 @@ func (s SQLiteRepository) UpdateSettings(ctx context.Context, se settings.Settin
 	theme := se.Theme
 
 	query := `INSERT INTO settings ("key", text_value)
-		VALUES (?, ?), (?, ?)
+		VALUES (?, ?), (?, ?), (?, ?), (?, ?)
 		ON CONFLICT ("key")
 		DO UPDATE SET 
 			text_value = EXCLUDED.text_value`
@@ -22,6 +22,8 @@ func (s SQLiteRepository) UpdateSettings(ctx context.Context, se settings.Settin
 	_, err := s.db.ExecContext(ctx, query,
 		settings.DelimitersKey, delimiters,
 		settings.ThemeKey, theme,
+		settings.RetentionDaysKey, settings.IntText(se.RetentionDays),
+		settings.RetentionRowsKey, settings.IntText(se.RetentionRows),
 	)
 
 	if err != nil {
@@ -16,15 +16,11 @@ import (
 
 // NewSQLiteRepository returns a new `SQLiteRepository`.
 func NewSQLiteRepository(e env.Environment) (*SQLiteRepository, error) {
-	x, err := sqlx.Open("sqlite", e.GetLocalRepositoryPath())
+	// Foreign keys are enabled on each connection in the pool, so deletes always cascade.
+	x, err := sqlx.Open("sqlite", e.GetLocalRepositoryPath()+"?_pragma=foreign_keys(1)")
 	if err != nil {
 		return nil, err
 	}
-
-	_, err = x.Exec("PRAGMA foreign_keys = ON;")
-	if err != nil {
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
 	x.SetMaxOpenConns(int(e.Repository.MaxConn))
 
 	return &SQLiteRepository{x}, nil
@@ -79,6 +75,11 @@ func (s SQLiteRepository) Fixture() error {
 	return nil
 }
 
+// Close implements `Repository.Close` for `SQLiteRepository`.
+func (s SQLiteRepository) Close() error {
+	return s.db.Close()
+}
+
 // Describe implements `Repository.Describe` for `SQLiteRepository`.
 func (s SQLiteRepository) Describe() []any {
 	return []any{"kind", "SQLite", "path", env.Env.GetLocalRepositoryPath()}
@@ -0,0 +1,23 @@
+#!/usr/bin/env sh
+
+# Usage: create.sh <certificate.pem> <key.pem> [ca.pem]
+
+json() {
+    awk '{ printf "%s\\n", $0 }' "$1"
+}
+
+CA_BUNDLE="null"
+if [ -n "$3" ]; then
+    CA_BUNDLE="\"$(json "$3")\""
+fi
+
+curl "http://127.0.0.1:${ZENIN_PORT}/api/v1/credential" \
+    -H "Authorization: Bearer ${ZENIN_SCRIPT_TOKEN}" \
+    -H "Content-Type: application/json" \
+    -d "{
+        \"name\": \"Internal API\",
+        \"certificate\": \"$(json "$1")\",
+        \"privateKey\": \"$(json "$2")\",
+        \"caBundle\": ${CA_BUNDLE}
+    }" \
+    -v
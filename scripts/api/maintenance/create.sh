@@ -0,0 +1,15 @@
+#!/usr/bin/env sh
+
+curl "http://127.0.0.1:${ZENIN_PORT}/api/v1/maintenance" \
+    -H "Authorization: Bearer ${ZENIN_SCRIPT_TOKEN}" \
+    -H "Content-Type: application/json" \
+    -d '{
+        "name": "Weekly database backup",
+        "mode": "SUPPRESS",
+        "startsAt": "2024-04-01T00:00:00Z",
+        "recurrence": "FREQ=WEEKLY;BYDAY=SA;BYHOUR=2;BYMINUTE=0",
+        "timezone": "Europe/Berlin",
+        "duration": 3600,
+        "monitorId": [1, 2]
+    }' \
+    -v
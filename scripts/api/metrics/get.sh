@@ -0,0 +1,5 @@
+#!/usr/bin/env sh
+
+curl "http://127.0.0.1:${ZENIN_PORT}/metrics" \
+    -H "Authorization: Bearer ${ZENIN_METRICS_TOKEN}" \
+    -v
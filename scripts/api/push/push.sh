@@ -0,0 +1,4 @@
+#!/usr/bin/env sh
+
+curl "http://127.0.0.1:${ZENIN_PORT}/api/v1/push/${ZENIN_PUSH_TOKEN}?state=OK&message=Backup%20finished.&duration=1500" \
+    -v
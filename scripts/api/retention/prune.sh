@@ -0,0 +1,5 @@
+#!/usr/bin/env sh
+
+curl -X POST "http://127.0.0.1:${ZENIN_PORT}/api/v1/retention" \
+    -H "Authorization: Bearer ${ZENIN_SCRIPT_TOKEN}" \
+    -v
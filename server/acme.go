@@ -0,0 +1,59 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// AcmeConfig contains options used to obtain certificates from an ACME directory.
+type AcmeConfig struct {
+	// URL of the ACME directory.
+	DirectoryURL string
+	// Domains that certificates are issued for. Requests for other names are rejected.
+	Domains []string
+	// Contact email registered with the account.
+	Email string
+	// Path to a PEM encoded certificate file trusted when connecting to the directory.
+	RootCAFile string
+	// Directory used to cache certificates and the account key.
+	CacheDir string
+}
+
+// NewAcmeManager returns an `autocert.Manager` that obtains and renews certificates
+// for the configured domains.
+//
+// Challenges are answered with TLS-ALPN-01 on the server port, and with HTTP-01 when the
+// handler returned by `HTTPHandler` is served on port 80.
+func NewAcmeManager(c AcmeConfig) (*autocert.Manager, error) {
+	client := &acme.Client{DirectoryURL: c.DirectoryURL}
+	if c.RootCAFile != "" {
+		pem, err := os.ReadFile(c.RootCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read acme root certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("acme root certificate file %v contains no certificates", c.RootCAFile)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		client.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(c.CacheDir),
+		HostPolicy: autocert.HostWhitelist(c.Domains...),
+		Email:      c.Email,
+		Client:     client,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestAcmeManager(t *testing.T) {
+	dir := t.TempDir()
+	config := AcmeConfig{
+		DirectoryURL: "https://127.0.0.1:14000/dir",
+		Domains:      []string{"example.com"},
+		CacheDir:     filepath.Join(dir, "acme"),
+	}
+
+	manager, err := NewAcmeManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.Assert(t, manager.HostPolicy(context.Background(), "example.com") == nil, "expected configured domain to be allowed")
+	debug.Assert(t, manager.HostPolicy(context.Background(), "example.org") != nil, "expected unknown domain to be rejected")
+
+	// Requests that are not challenges are redirected.
+	request := httptest.NewRequest(http.MethodGet, "/api/v1", nil)
+	request.Host = "example.com"
+	recorder := httptest.NewRecorder()
+	manager.HTTPHandler(NewRedirect(443)).ServeHTTP(recorder, request)
+	debug.AssertEqual(t, recorder.Code, http.StatusMovedPermanently)
+	debug.AssertEqual(t, recorder.Header().Get("Location"), "https://example.com/api/v1")
+
+	// A root certificate file without certificates is rejected.
+	config.RootCAFile = filepath.Join(dir, "root.pem")
+	if err := os.WriteFile(config.RootCAFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewAcmeManager(config)
+	debug.Assert(t, err != nil, "expected invalid root certificate file to be rejected")
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/credential"
+	"github.com/jmkng/zenin/internal/env"
+)
+
+func NewCredentialHandler(service credential.CredentialService) CredentialHandler {
+	provider := NewCredentialProvider(service)
+	return CredentialHandler{Provider: provider, mux: provider.Mux()}
+}
+
+type CredentialHandler struct {
+	Provider CredentialProvider
+	mux      http.Handler
+}
+
+func (c CredentialHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mux.ServeHTTP(w, r)
+}
+
+func NewCredentialProvider(service credential.CredentialService) CredentialProvider {
+	return CredentialProvider{
+		Service: service,
+	}
+}
+
+// CredentialProvider handles credential requests.
+//
+// Private keys are accepted, but never returned.
+type CredentialProvider struct {
+	Service credential.CredentialService
+}
+
+func (c CredentialProvider) Mux() http.Handler {
+	router := chi.NewRouter()
+	router.Get("/", c.HandleGetCredentials)
+	router.Post("/", c.HandleCreateCredential)
+	router.Delete("/", c.HandleDeleteCredential)
+	router.Put("/{id}", c.HandleUpdateCredential)
+	return router
+}
+
+func (c CredentialProvider) HandleGetCredentials(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	credentials, err := c.Service.GetCredentials(r.Context())
+	if err != nil {
+		responder.Error(err, http.StatusInternalServerError)
+		return
+	}
+	if credentials == nil {
+		credentials = make([]credential.Credential, 0)
+	}
+
+	responder.Data(struct {
+		Credentials []credential.Credential `json:"credentials"`
+	}{Credentials: credentials}, http.StatusOK)
+}
+
+func (c CredentialProvider) HandleCreateCredential(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	var application credential.Application
+	err := StrictDecoder(r.Body).Decode(&application)
+	if err != nil {
+		responder.Error(env.NewValidation("Expected `name` key, and `certificate`, `privateKey` or `caBundle` keys."),
+			http.StatusBadRequest)
+		return
+	}
+
+	id, time, err := c.Service.CreateCredential(r.Context(), application)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.As(err, &env.Validation{}) {
+			status = http.StatusBadRequest
+		}
+
+		responder.Error(err, status)
+		return
+	}
+
+	responder.Data(internal.CreatedTimestampValue{
+		Id:             id,
+		TimestampValue: time,
+	}, http.StatusCreated)
+}
+
+func (c CredentialProvider) HandleUpdateCredential(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	param := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(param)
+	if err != nil {
+		responder.Error(env.NewValidation("Expected integer url parameter."),
+			http.StatusBadRequest)
+		return
+	}
+
+	var application credential.Application
+	err = StrictDecoder(r.Body).Decode(&application)
+	if err != nil {
+		responder.Error(env.NewValidation("Expected `name` key, and `certificate`, `privateKey` or `caBundle` keys."),
+			http.StatusBadRequest)
+		return
+	}
+
+	time, err := c.Service.UpdateCredential(r.Context(), id, application)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.As(err, &env.Validation{}) {
+			status = http.StatusBadRequest
+		}
+
+		responder.Error(err, status)
+		return
+	}
+
+	responder.Data(time, http.StatusOK)
+}
+
+func (c CredentialProvider) HandleDeleteCredential(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	id := scanQueryParameterIds(r.URL.Query())
+	if len(id) == 0 {
+		responder.Error(env.NewValidation("Expected `id` query parameter."),
+			http.StatusBadRequest)
+		return
+	}
+
+	err := c.Service.Repository.DeleteCredential(r.Context(), id)
+	if err != nil {
+		responder.Error(err, http.StatusInternalServerError)
+		return
+	}
+
+	responder.Status(http.StatusOK)
+}
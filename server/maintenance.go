@@ -0,0 +1,143 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmkng/zenin/internal"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/maintenance"
+)
+
+func NewMaintenanceHandler(service maintenance.MaintenanceService) MaintenanceHandler {
+	provider := NewMaintenanceProvider(service)
+	return MaintenanceHandler{Provider: provider, mux: provider.Mux()}
+}
+
+type MaintenanceHandler struct {
+	Provider MaintenanceProvider
+	mux      http.Handler
+}
+
+func (m MaintenanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mux.ServeHTTP(w, r)
+}
+
+func NewMaintenanceProvider(service maintenance.MaintenanceService) MaintenanceProvider {
+	return MaintenanceProvider{
+		Service: service,
+	}
+}
+
+// MaintenanceProvider handles maintenance window requests.
+type MaintenanceProvider struct {
+	Service maintenance.MaintenanceService
+}
+
+func (m MaintenanceProvider) Mux() http.Handler {
+	router := chi.NewRouter()
+	router.Get("/", m.HandleGetMaintenance)
+	router.Post("/", m.HandleCreateMaintenance)
+	router.Delete("/", m.HandleDeleteMaintenance)
+	router.Put("/{id}", m.HandleUpdateMaintenance)
+	return router
+}
+
+func (m MaintenanceProvider) HandleGetMaintenance(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	windows, err := m.Service.GetMaintenance(r.Context())
+	if err != nil {
+		responder.Error(err, http.StatusInternalServerError)
+		return
+	}
+	if windows == nil {
+		windows = make([]maintenance.Maintenance, 0)
+	}
+
+	responder.Data(struct {
+		Maintenance []maintenance.Maintenance `json:"maintenance"`
+	}{Maintenance: windows}, http.StatusOK)
+}
+
+func (m MaintenanceProvider) HandleCreateMaintenance(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	var window maintenance.Maintenance
+	err := StrictDecoder(r.Body).Decode(&window)
+	if err != nil {
+		responder.Error(env.NewValidation("Expected `name`, `mode`, `startsAt` and `monitorId` keys."),
+			http.StatusBadRequest)
+		return
+	}
+
+	id, time, err := m.Service.CreateMaintenance(r.Context(), window)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.As(err, &env.Validation{}) {
+			status = http.StatusBadRequest
+		}
+
+		responder.Error(err, status)
+		return
+	}
+
+	responder.Data(internal.CreatedTimestampValue{
+		Id:             id,
+		TimestampValue: time,
+	}, http.StatusCreated)
+}
+
+func (m MaintenanceProvider) HandleUpdateMaintenance(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	param := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(param)
+	if err != nil {
+		responder.Error(env.NewValidation("Expected integer url parameter."),
+			http.StatusBadRequest)
+		return
+	}
+
+	var window maintenance.Maintenance
+	err = StrictDecoder(r.Body).Decode(&window)
+	if err != nil {
+		responder.Error(env.NewValidation("Expected `name`, `mode`, `startsAt` and `monitorId` keys."),
+			http.StatusBadRequest)
+		return
+	}
+
+	time, err := m.Service.UpdateMaintenance(r.Context(), id, window)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.As(err, &env.Validation{}) {
+			status = http.StatusBadRequest
+		}
+
+		responder.Error(err, status)
+		return
+	}
+
+	responder.Data(time, http.StatusOK)
+}
+
+func (m MaintenanceProvider) HandleDeleteMaintenance(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	id := scanQueryParameterIds(r.URL.Query())
+	if len(id) == 0 {
+		responder.Error(env.NewValidation("Expected `id` query parameter."),
+			http.StatusBadRequest)
+		return
+	}
+
+	err := m.Service.DeleteMaintenance(r.Context(), id)
+	if err != nil {
+		responder.Error(err, http.StatusInternalServerError)
+		return
+	}
+
+	responder.Status(http.StatusOK)
+}
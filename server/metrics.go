@@ -0,0 +1,81 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/metrics"
+	"github.com/jmkng/zenin/internal/monitor"
+)
+
+func NewMetricsHandler(service monitor.MonitorService, token env.Secret) MetricsHandler {
+	provider := NewMetricsProvider(service, token)
+	return MetricsHandler{Provider: provider, mux: provider.Mux()}
+}
+
+type MetricsHandler struct {
+	Provider MetricsProvider
+	mux      http.Handler
+}
+
+func (h MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func NewMetricsProvider(service monitor.MonitorService, token env.Secret) MetricsProvider {
+	return MetricsProvider{
+		Service:  service,
+		Registry: metrics.Default,
+		token:    token,
+	}
+}
+
+// MetricsProvider serves metrics in the Prometheus text exposition format.
+type MetricsProvider struct {
+	Service  monitor.MonitorService
+	Registry *metrics.Registry
+
+	// Required as a bearer token when not empty.
+	token env.Secret
+}
+
+func (p MetricsProvider) Mux() http.Handler {
+	router := chi.NewRouter()
+	router.Get("/", p.HandleGetMetrics)
+	return router
+}
+
+// HandleGetMetrics responds with the current metrics.
+func (p MetricsProvider) HandleGetMetrics(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	if len(p.token) > 0 {
+		token := extractBearerToken(r.Header.Get(Authorization))
+		if subtle.ConstantTimeCompare([]byte(token), p.token) != 1 {
+			responder.Status(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	stats, err := p.Service.GetDistributorStats(r.Context())
+	if err != nil {
+		responder.Error(err, http.StatusServiceUnavailable)
+		return
+	}
+	p.Registry.Set(metrics.DistributorMonitors, float64(stats.Monitors))
+	p.Registry.Set(metrics.DistributorSubscribers, float64(stats.Subscribers))
+	for pool, v := range map[string]monitor.PoolStats{"probes": stats.Probes, "events": stats.Events} {
+		p.Registry.Set(metrics.DistributorQueueDepth, float64(v.Queued), "pool", pool)
+		p.Registry.Set(metrics.DistributorQueueCapacity, float64(v.Capacity), "pool", pool)
+		p.Registry.Set(metrics.DistributorActive, float64(v.Active), "pool", pool)
+		p.Registry.Set(metrics.DistributorDropped, float64(v.Dropped), "pool", pool)
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.WriteHeader(http.StatusOK)
+	if err := p.Registry.Write(w); err != nil {
+		env.Debug("failed to write metrics", "error", err)
+	}
+}
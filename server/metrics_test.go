@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jmkng/zenin/internal/debug"
+	"github.com/jmkng/zenin/internal/metrics"
+	"github.com/jmkng/zenin/internal/monitor"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	distributor := make(chan any)
+	defer close(distributor)
+	go func() {
+		for message := range distributor {
+			if x, ok := message.(monitor.StatsMessage); ok {
+				x.Reply <- monitor.DistributorStats{Monitors: 3, Subscribers: 2}
+			}
+		}
+	}()
+	service := monitor.NewMonitorService(nil, distributor)
+	handler := NewMetricsHandler(service, []byte("secret"))
+
+	for _, v := range []struct {
+		header string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusOK},
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if v.header != "" {
+			request.Header.Set(Authorization, v.header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		debug.AssertEqual(t, recorder.Code, v.status)
+
+		if v.status == http.StatusOK {
+			debug.AssertEqual(t, recorder.Header().Get("Content-Type"), metrics.ContentType)
+			body := recorder.Body.String()
+			debug.Assert(t, strings.Contains(body, "zenin_distributor_monitors 3"), "expected monitor count")
+			debug.Assert(t, strings.Contains(body, `zenin_distributor_queue_depth{pool="probes"} 0`), "expected probe queue depth")
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -13,10 +14,12 @@ import (
 	"github.com/jmkng/zenin/internal/env"
 	"github.com/jmkng/zenin/internal/measurement"
 	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/rollup"
+	"github.com/jmkng/zenin/internal/stats"
 )
 
-func NewMonitorHandler(service monitor.MonitorService) MonitorHandler {
-	provider := NewMonitorProvider(service)
+func NewMonitorHandler(service monitor.MonitorService, rollups rollup.RollupService, stats stats.StatsService) MonitorHandler {
+	provider := NewMonitorProvider(service, rollups, stats)
 	return MonitorHandler{Provider: provider, mux: provider.Mux()}
 }
 
@@ -29,14 +32,18 @@ func (h MonitorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.mux.ServeHTTP(w, r)
 }
 
-func NewMonitorProvider(service monitor.MonitorService) MonitorProvider {
+func NewMonitorProvider(service monitor.MonitorService, rollups rollup.RollupService, stats stats.StatsService) MonitorProvider {
 	return MonitorProvider{
 		Service: service,
+		Rollup:  rollups,
+		Stats:   stats,
 	}
 }
 
 type MonitorProvider struct {
 	Service monitor.MonitorService
+	Rollup  rollup.RollupService
+	Stats   stats.StatsService
 }
 
 func (m MonitorProvider) Mux() http.Handler {
@@ -47,8 +54,10 @@ func (m MonitorProvider) Mux() http.Handler {
 	router.Patch("/", m.HandleToggleMonitor)
 	router.Put("/{id}", m.HandleUpdateMonitor)
 	router.Get("/{id}/measurements", m.HandleGetMeasurements)
+	router.Get("/{id}/stats", m.HandleGetStats)
 	router.Get("/{id}/poll", m.HandlePollMonitor)
 	router.Get("/plugins", m.HandleGetPlugins)
+	router.Get("/distributor", m.HandleGetDistributor)
 	return router
 }
 
@@ -140,16 +149,12 @@ func (m MonitorProvider) HandleDeleteMonitor(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	err := m.Service.Repository.DeleteMonitor(r.Context(), *params.Id)
+	err := m.Service.DeleteMonitor(r.Context(), *params.Id)
 	if err != nil {
 		responder.Error(err, http.StatusInternalServerError)
 		return
 	}
 
-	for _, v := range *params.Id {
-		m.Service.Distributor <- monitor.StopMessage{Id: v}
-	}
-
 	responder.Status(http.StatusOK)
 }
 
@@ -248,7 +253,29 @@ func (m MonitorProvider) HandleGetMeasurements(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	params := newSelectMeasurementParamsFromQuery(r.URL.Query())
+	query := r.URL.Query()
+	params := newSelectMeasurementParamsFromQuery(query)
+	resolution, err := newResolutionFromQuery(query, params, time.Now())
+	if err != nil {
+		responder.Error(env.NewValidation("Expected `resolution` query parameter to be `raw`, `hour` or `day`."),
+			http.StatusBadRequest)
+		return
+	}
+
+	if resolution != nil {
+		rollups, err := m.Rollup.GetRollups(r.Context(), pid, *resolution,
+			&rollup.SelectRollupParams{After: params.After, Before: params.Before})
+		if err != nil {
+			responder.Error(err, http.StatusInternalServerError)
+			return
+		}
+		responder.Data(struct {
+			Resolution   rollup.Resolution         `json:"resolution"`
+			Measurements []measurement.Measurement `json:"measurements"`
+			Rollups      []rollup.Rollup           `json:"rollups"`
+		}{Resolution: *resolution, Measurements: []measurement.Measurement{}, Rollups: rollups}, http.StatusOK)
+		return
+	}
 
 	measurements, err := m.
 		Service.
@@ -256,14 +283,55 @@ func (m MonitorProvider) HandleGetMeasurements(w http.ResponseWriter, r *http.Re
 		SelectMeasurement(r.Context(), pid, &params)
 	if err != nil {
 		responder.Error(err, http.StatusInternalServerError)
+		return
 	}
 	if measurements == nil {
 		measurements = make([]measurement.Measurement, 0)
 	}
 
 	responder.Data(struct {
+		Resolution   string                    `json:"resolution"`
 		Measurements []measurement.Measurement `json:"measurements"`
-	}{Measurements: measurements}, http.StatusOK)
+		Rollups      []rollup.Rollup           `json:"rollups"`
+	}{Resolution: rawResolution, Measurements: measurements, Rollups: []rollup.Rollup{}}, http.StatusOK)
+}
+
+// HandleGetStats responds with the availability statistics of a monitor over a time range.
+func (m MonitorProvider) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	rid := chi.URLParam(r, "id")
+	pid, err := strconv.Atoi(rid)
+	if err != nil {
+		responder.Error(env.NewValidation("Expected integer url parameter."),
+			http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	from, to, err := newStatsRangeFromQuery(query, time.Now())
+	if err != nil {
+		responder.Error(err, http.StatusBadRequest)
+		return
+	}
+	maintenance := query.Get("maintenance") == "include"
+
+	result, found, err := m.Stats.GetStats(r.Context(), pid, from, to, maintenance)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.As(err, &env.Validation{}) {
+			status = http.StatusBadRequest
+		}
+		responder.Error(err, status)
+		return
+	}
+	if !found {
+		message := fmt.Sprintf("Monitor with id `%v` does not exist.", pid)
+		responder.Error(env.NewValidation(message), http.StatusNotFound)
+		return
+	}
+
+	responder.Data(result, http.StatusOK)
 }
 
 func (m MonitorProvider) HandlePollMonitor(w http.ResponseWriter, r *http.Request) {
@@ -288,6 +356,10 @@ func (m MonitorProvider) HandlePollMonitor(w http.ResponseWriter, r *http.Reques
 		responder.Error(env.NewValidation(message), http.StatusBadRequest)
 		return
 	}
+	if found[0].Kind == measurement.Push {
+		responder.Error(env.NewValidation("Push monitors can't be polled."), http.StatusBadRequest)
+		return
+	}
 
 	m.Service.Distributor <- monitor.PollMessage{Monitor: found[0]}
 
@@ -312,6 +384,18 @@ func (m MonitorProvider) HandleGetPlugins(w http.ResponseWriter, r *http.Request
 
 }
 
+func (m MonitorProvider) HandleGetDistributor(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	stats, err := m.Service.GetDistributorStats(r.Context())
+	if err != nil {
+		responder.Error(err, http.StatusInternalServerError)
+		return
+	}
+
+	responder.Data(stats, http.StatusOK)
+}
+
 // newSelectMonitorParamsFromQuery returns a `SelectMonitorParams` by parsing the values from
 // a `net/http` query string.
 func newSelectMonitorParamsFromQuery(values url.Values) monitor.SelectMonitorParams {
@@ -362,3 +446,74 @@ func newSelectMeasurementParamsFromQuery(values url.Values) monitor.SelectMeasur
 
 	return params
 }
+
+// rawResolution is the value of the `resolution` query parameter that selects raw measurements.
+const rawResolution = "RAW"
+
+// newResolutionFromQuery returns the rollup `Resolution` requested by the `resolution` value
+// of a `net/http` query string, or nil if raw measurements are requested.
+//
+// When the value is missing, the resolution is chosen by the width of the time range.
+// A range without a lower bound is answered from raw measurements.
+func newResolutionFromQuery(values url.Values, params monitor.SelectMeasurementParams, now time.Time) (*rollup.Resolution, error) {
+	if raw := values.Get("resolution"); raw != "" {
+		if strings.EqualFold(raw, rawResolution) {
+			return nil, nil
+		}
+		resolution, err := rollup.ResolutionFromString(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &resolution, nil
+	}
+
+	if params.After == nil {
+		return nil, nil
+	}
+	before := now
+	if params.Before != nil {
+		before = params.Before.Time()
+	}
+	return rollup.ResolutionFor(params.After.Time(), before), nil
+}
+
+// defaultStatsRange is the width of the time range used for statistics when `from` is omitted.
+const defaultStatsRange = 30 * 24 * time.Hour
+
+// newStatsRangeFromQuery returns the time range described by the `from` and `to` values
+// of a `net/http` query string. Both accept RFC 3339 or a date such as `1/2/2006`.
+//
+// The range ends at `now` when `to` is omitted, and starts `defaultStatsRange` before
+// the end when `from` is omitted.
+func newStatsRangeFromQuery(values url.Values, now time.Time) (time.Time, time.Time, error) {
+	parse := func(key string) (*time.Time, error) {
+		raw := values.Get(key)
+		if raw == "" {
+			return nil, nil
+		}
+		for _, format := range []string{time.RFC3339, "1/2/2006"} {
+			if parsed, err := time.Parse(format, raw); err == nil {
+				return &parsed, nil
+			}
+		}
+		return nil, env.NewValidation(fmt.Sprintf("Expected `%v` query parameter to be an RFC 3339 time or a date.", key))
+	}
+
+	to := now
+	if parsed, err := parse("to"); err != nil {
+		return time.Time{}, time.Time{}, err
+	} else if parsed != nil {
+		to = *parsed
+	}
+	from := to.Add(-defaultStatsRange)
+	if parsed, err := parse("from"); err != nil {
+		return time.Time{}, time.Time{}, err
+	} else if parsed != nil {
+		from = *parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, env.NewValidation("Expected `from` query parameter to be before `to`.")
+	}
+	return from, to, nil
+}
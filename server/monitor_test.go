@@ -1,10 +1,13 @@
 package server
 
 import (
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/jmkng/zenin/internal/debug"
 	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/rollup"
 )
 
 func TestSelectParamsFromQuery(t *testing.T) {
@@ -23,3 +26,44 @@ func TestSelectParamsFromQuery(t *testing.T) {
 	active := true
 	debug.AssertEqual(t, *params.Active, active)
 }
+
+func TestResolutionFromQuery(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	values := url.Values{"after": []string{"3/9/2024"}}
+	resolution, err := newResolutionFromQuery(values, newSelectMeasurementParamsFromQuery(values), now)
+	debug.Assert(t, err == nil && resolution == nil, "expected raw measurements for a short range")
+
+	values = url.Values{"after": []string{"1/1/2024"}, "before": []string{"1/15/2024"}}
+	resolution, err = newResolutionFromQuery(values, newSelectMeasurementParamsFromQuery(values), now)
+	debug.Assert(t, err == nil, "expected no error")
+	debug.AssertEqual(t, *resolution, rollup.Hour)
+
+	values = url.Values{"after": []string{"1/1/2023"}, "resolution": []string{"raw"}}
+	resolution, err = newResolutionFromQuery(values, newSelectMeasurementParamsFromQuery(values), now)
+	debug.Assert(t, err == nil && resolution == nil, "expected explicit raw resolution")
+
+	values = url.Values{"resolution": []string{"week"}}
+	_, err = newResolutionFromQuery(values, newSelectMeasurementParamsFromQuery(values), now)
+	debug.Assert(t, err != nil, "expected error for unknown resolution")
+}
+
+func TestStatsRangeFromQuery(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	from, to, err := newStatsRangeFromQuery(url.Values{}, now)
+	debug.Assert(t, err == nil, "expected no error")
+	debug.AssertEqual(t, to, now)
+	debug.AssertEqual(t, from, now.Add(-defaultStatsRange))
+
+	values := url.Values{"from": []string{"2/1/2024"}, "to": []string{"2024-03-01T00:00:00Z"}}
+	from, to, err = newStatsRangeFromQuery(values, now)
+	debug.Assert(t, err == nil, "expected no error")
+	debug.AssertEqual(t, from, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	debug.AssertEqual(t, to, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	_, _, err = newStatsRangeFromQuery(url.Values{"from": []string{"3/11/2024"}}, now)
+	debug.Assert(t, err != nil, "expected error for range that ends before it starts")
+	_, _, err = newStatsRangeFromQuery(url.Values{"to": []string{"yesterday"}}, now)
+	debug.Assert(t, err != nil, "expected error for unparsable time")
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/measurement"
+	"github.com/jmkng/zenin/internal/monitor"
+)
+
+func NewPushHandler(service monitor.MonitorService) PushHandler {
+	provider := NewPushProvider(service)
+	return PushHandler{Provider: provider, mux: provider.Mux()}
+}
+
+type PushHandler struct {
+	Provider PushProvider
+	mux      http.Handler
+}
+
+func (p PushHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.ServeHTTP(w, r)
+}
+
+func NewPushProvider(service monitor.MonitorService) PushProvider {
+	return PushProvider{
+		Service: service,
+	}
+}
+
+// PushProvider receives pushes for `PUSH` monitors.
+//
+// Requests are authenticated by the secret token in the URL, not an account token,
+// so jobs can push without signing in.
+type PushProvider struct {
+	Service monitor.MonitorService
+}
+
+func (p PushProvider) Mux() http.Handler {
+	router := chi.NewRouter()
+	router.Get("/{token}", p.HandlePush)
+	router.Post("/{token}", p.HandlePush)
+	return router
+}
+
+// HandlePush accepts the optional `state`, `message` and `duration` query parameters.
+func (p PushProvider) HandlePush(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	query := r.URL.Query()
+	validation := env.NewValidation()
+	push := monitor.Push{State: measurement.Ok}
+	if x := query.Get("state"); x != "" {
+		switch state := measurement.ProbeState(strings.ToUpper(x)); state {
+		case measurement.Ok, measurement.Warn, measurement.Dead:
+			push.State = state
+		default:
+			validation.Push("Expected `state` query parameter to be `OK`, `WARN` or `DEAD`.")
+		}
+	}
+	if x := query.Get("message"); x != "" {
+		push.Message = &x
+	}
+	if x := query.Get("duration"); x != "" {
+		duration, err := strconv.ParseFloat(x, 64)
+		if err != nil || duration < 0 {
+			validation.Push("Expected `duration` query parameter to be a positive number of milliseconds.")
+		} else {
+			push.Duration = &duration
+		}
+	}
+	if !validation.Empty() {
+		responder.Error(validation, http.StatusBadRequest)
+		return
+	}
+
+	found, ok, err := p.Service.GetPushMonitor(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		responder.Error(err, http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		responder.Status(http.StatusNotFound)
+		return
+	}
+
+	p.Service.Distributor <- monitor.PushMessage{Id: *found.Id, Push: push}
+
+	responder.Status(http.StatusAccepted)
+}
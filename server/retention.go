@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmkng/zenin/internal/retention"
+)
+
+func NewRetentionHandler(service retention.RetentionService) RetentionHandler {
+	provider := NewRetentionProvider(service)
+	return RetentionHandler{Provider: provider, mux: provider.Mux()}
+}
+
+type RetentionHandler struct {
+	Provider RetentionProvider
+	mux      http.Handler
+}
+
+func (h RetentionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func NewRetentionProvider(service retention.RetentionService) RetentionProvider {
+	return RetentionProvider{
+		Service: service,
+	}
+}
+
+// RetentionProvider handles measurement retention requests.
+type RetentionProvider struct {
+	Service retention.RetentionService
+}
+
+func (p RetentionProvider) Mux() http.Handler {
+	router := chi.NewRouter()
+	router.Get("/", p.HandleGetRuns)
+	router.Post("/", p.HandlePrune)
+	return router
+}
+
+// HandleGetRuns responds with the most recent pruning runs.
+func (p RetentionProvider) HandleGetRuns(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	responder.Data(struct {
+		Runs []retention.Run `json:"runs"`
+	}{Runs: p.Service.GetRuns()}, http.StatusOK)
+}
+
+// HandlePrune starts a pruning run in the background.
+func (p RetentionProvider) HandlePrune(w http.ResponseWriter, r *http.Request) {
+	responder := NewResponder(w)
+
+	p.Service.Trigger()
+	responder.Status(http.StatusAccepted)
+}
@@ -1,9 +1,12 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
 	"embed"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"mime"
@@ -11,6 +14,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -18,10 +22,15 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jmkng/zenin/internal/account"
+	"github.com/jmkng/zenin/internal/credential"
 	"github.com/jmkng/zenin/internal/env"
+	"github.com/jmkng/zenin/internal/maintenance"
 	"github.com/jmkng/zenin/internal/measurement"
 	"github.com/jmkng/zenin/internal/monitor"
+	"github.com/jmkng/zenin/internal/retention"
+	"github.com/jmkng/zenin/internal/rollup"
 	"github.com/jmkng/zenin/internal/settings"
+	"github.com/jmkng/zenin/internal/stats"
 )
 
 func NewConfig(e env.Environment) (Config, error) {
@@ -35,7 +44,20 @@ func NewConfig(e env.Environment) (Config, error) {
 		Port: int(e.Port),
 	}
 
-	return Config{Env: e, Address: address, Tls: nil}, nil // TODO: TLS setup will happen here.
+	var tc *TlsConfig
+	if len(e.ACMEDomains) > 0 {
+		tc = &TlsConfig{Acme: &AcmeConfig{
+			DirectoryURL: e.ACMEDirectory,
+			Domains:      e.ACMEDomains,
+			Email:        e.ACMEEmail,
+			RootCAFile:   e.ACMERootCA,
+			CacheDir:     filepath.Join(e.BaseDir, "acme"),
+		}}
+	} else if e.TLSCert != "" && e.TLSKey != "" {
+		tc = &TlsConfig{CertFile: e.TLSCert, KeyFile: e.TLSKey}
+	}
+
+	return Config{Env: e, Address: address, Tls: tc}, nil
 }
 
 // Config controls the behavior of a Zenin Server.
@@ -47,8 +69,12 @@ type Config struct {
 
 // TlsConfig contains TLS configuration options for the Zenin Server.
 type TlsConfig struct {
-	Cert []byte
-	Key  []byte
+	// Path to a PEM encoded certificate file.
+	CertFile string
+	// Path to a PEM encoded private key file.
+	KeyFile string
+	// Obtains certificates from an ACME directory instead of the files when not nil.
+	Acme *AcmeConfig
 }
 
 // StrictDecoder returns a `*json.Decoder` with `DisallowUnknownFields` set.
@@ -63,6 +89,11 @@ type Services struct {
 	Measurement measurement.MeasurementService
 	Monitor     monitor.MonitorService
 	Account     account.AccountService
+	Credential  credential.CredentialService
+	Maintenance maintenance.MaintenanceService
+	Retention   retention.RetentionService
+	Rollup      rollup.RollupService
+	Stats       stats.StatsService
 }
 
 // NewServer returns a new `Server`.
@@ -76,14 +107,22 @@ type Server struct {
 	services Services
 }
 
-// Listen will block and listen for incoming requests.
-func (s *Server) Serve() error {
+// Serve will block and listen for incoming requests until the context is done.
+//
+// The server then stops accepting connections, and waits up to `timeout` for
+// active requests to finish.
+func (s *Server) Serve(ctx context.Context, timeout time.Duration) error {
 	env.Info("server starting", "address", s.config.Address.IP.String(), "port", s.config.Address.Port)
 
 	settings := s.services.Settings
 	account := s.services.Account
 	monitor := s.services.Monitor
 	measurement := s.services.Measurement
+	credential := s.services.Credential
+	maintenance := s.services.Maintenance
+	retention := s.services.Retention
+	rollup := s.services.Rollup
+	stats := s.services.Stats
 
 	mux := chi.NewRouter()
 	if s.config.Env.AllowInsecure {
@@ -97,10 +136,14 @@ func (s *Server) Serve() error {
 	v1.Mount("/settings", NewSettingsHandler(settings))
 	v1.Mount("/account", NewAccountHandler(account))
 	v1.Mount("/feed", NewFeedHandler(monitor))
+	v1.Mount("/push", NewPushHandler(monitor))
 	v1.Group(func(private chi.Router) {
 		private.Use(Authenticate)
-		private.Mount("/monitor", NewMonitorHandler(monitor))
+		private.Mount("/monitor", NewMonitorHandler(monitor, rollup, stats))
 		private.Mount("/measurement", NewMeasurementHandler(measurement))
+		private.Mount("/credential", NewCredentialHandler(credential))
+		private.Mount("/maintenance", NewMaintenanceHandler(maintenance))
+		private.Mount("/retention", NewRetentionHandler(retention))
 	})
 
 	api := chi.NewRouter()
@@ -108,15 +151,71 @@ func (s *Server) Serve() error {
 	api.NotFound(notFoundHandler)
 
 	mux.Mount("/api", api)
+	mux.Mount("/metrics", NewMetricsHandler(monitor, s.config.Env.MetricsToken))
 	mux.Mount("/", NewEmbed(settings))
 
-	err := http.ListenAndServe(s.config.Address.String(), mux)
-	if err != nil {
-		return err
+	server := &http.Server{Addr: s.config.Address.String(), Handler: mux}
+	servers := []*http.Server{server}
+	errs := make(chan error, 2)
+
+	if s.config.Tls == nil {
+		go func() {
+			errs <- server.ListenAndServe()
+		}()
+	} else {
+		redirectHandler := NewRedirect(s.config.Address.Port)
+		if acme := s.config.Tls.Acme; acme != nil {
+			manager, err := NewAcmeManager(*acme)
+			if err != nil {
+				return err
+			}
+			server.TLSConfig = manager.TLSConfig()
+			server.TLSConfig.MinVersion = tls.VersionTLS12
+			// Answers HTTP-01 challenges, and redirects everything else.
+			redirectHandler = manager.HTTPHandler(redirectHandler)
+			env.Info("server tls enabled", "acme", acme.DirectoryURL, "domains", acme.Domains)
+		} else {
+			loader, err := NewCertificateLoader(s.config.Tls.CertFile, s.config.Tls.KeyFile)
+			if err != nil {
+				return err
+			}
+			go loader.Watch(ctx)
+
+			server.TLSConfig = &tls.Config{GetCertificate: loader.GetCertificate, MinVersion: tls.VersionTLS12}
+			env.Info("server tls enabled", "cert", s.config.Tls.CertFile)
+		}
+		go func() {
+			errs <- server.ListenAndServeTLS("", "")
+		}()
+
+		if s.config.Env.RedirectPort != 0 {
+			address := net.TCPAddr{IP: s.config.Address.IP, Port: int(s.config.Env.RedirectPort)}
+			redirect := &http.Server{Addr: address.String(), Handler: redirectHandler}
+			servers = append(servers, redirect)
+			go func() {
+				errs <- redirect.ListenAndServe()
+			}()
+			env.Info("server redirecting to https", "port", address.Port)
+		}
+	}
+
+	// failure is the error of the first server that stopped on its own, such as a listener
+	// that could not bind. The other servers are still shut down.
+	var failure error
+	select {
+	case failure = <-errs:
+	case <-ctx.Done():
 	}
 
-	env.Debug("server stopping")
-	return nil
+	env.Info("server stopping")
+	shutdown, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, v := range servers {
+		if err := v.Shutdown(shutdown); err != nil {
+			return errors.Join(failure, fmt.Errorf("failed to stop server: %w", err))
+		}
+	}
+	return failure
 }
 
 // scanQueryParameterIds will return all comma separated ids in the value map.
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jmkng/zenin/internal/env"
+)
+
+// certificateCheckInterval is the time between checks for a changed certificate or key file.
+const certificateCheckInterval = 30 * time.Second
+
+// NewCertificateLoader returns a new `CertificateLoader` with the certificate loaded.
+func NewCertificateLoader(certFile, keyFile string) (*CertificateLoader, error) {
+	l := &CertificateLoader{certFile: certFile, keyFile: keyFile}
+	if err := l.Load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// CertificateLoader holds a certificate read from a certificate and key file,
+// and reloads it when the files change.
+// Safe for concurrent use.
+type CertificateLoader struct {
+	certFile string
+	keyFile  string
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	modified    time.Time
+}
+
+// Load will read the certificate and key files.
+// The current certificate is kept if they can't be read.
+func (l *CertificateLoader) Load() error {
+	modified, err := l.lastModified()
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	l.mutex.Lock()
+	l.certificate = &certificate
+	l.modified = modified
+	l.mutex.Unlock()
+	return nil
+}
+
+// GetCertificate implements `tls.Config.GetCertificate` for `CertificateLoader`.
+func (l *CertificateLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.certificate, nil
+}
+
+// Watch will block and reload the certificate on SIGHUP, or when either file changes,
+// until the context is done.
+func (l *CertificateLoader) Watch(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(certificateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			l.reload("signal")
+		case <-ticker.C:
+			if l.changed() {
+				l.reload("file change")
+			}
+		}
+	}
+}
+
+// changed returns true if either file was modified after the certificate was loaded.
+func (l *CertificateLoader) changed() bool {
+	modified, err := l.lastModified()
+	if err != nil {
+		return false
+	}
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return modified.After(l.modified)
+}
+
+func (l *CertificateLoader) reload(reason string) {
+	if err := l.Load(); err != nil {
+		env.Error("failed to reload certificate, keeping current certificate", "reason", reason, "error", err)
+		return
+	}
+	env.Info("reloaded certificate", "reason", reason, "cert", l.certFile)
+}
+
+// lastModified returns the most recent modification time of the certificate and key file.
+func (l *CertificateLoader) lastModified() (time.Time, error) {
+	var latest time.Time
+	for _, v := range []string{l.certFile, l.keyFile} {
+		info, err := os.Stat(v)
+		if err != nil {
+			return latest, fmt.Errorf("failed to stat certificate file: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// NewRedirect returns an `http.Handler` that permanently redirects requests
+// to the same path over HTTPS on the port.
+func NewRedirect(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
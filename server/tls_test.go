@@ -0,0 +1,111 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmkng/zenin/internal/debug"
+)
+
+func TestCertificateLoader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	writeCertificate(t, certFile, keyFile, "first")
+	loader, err := NewCertificateLoader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug.AssertEqual(t, leafName(t, loader), "first")
+	debug.Assert(t, !loader.changed(), "unexpected change before files are written")
+
+	// Renewed files are picked up on the next check.
+	writeCertificate(t, certFile, keyFile, "second")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	debug.Assert(t, loader.changed(), "expected change after files are written")
+	loader.reload("test")
+	debug.AssertEqual(t, leafName(t, loader), "second")
+
+	// A broken renewal keeps the current certificate.
+	if err := os.WriteFile(keyFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	loader.reload("test")
+	debug.AssertEqual(t, leafName(t, loader), "second")
+}
+
+func TestRedirect(t *testing.T) {
+	cases := []struct {
+		port   int
+		host   string
+		expect string
+	}{
+		{23111, "example.com", "https://example.com:23111/api/v1?x=1"},
+		{23111, "example.com:80", "https://example.com:23111/api/v1?x=1"},
+		{443, "example.com:80", "https://example.com/api/v1?x=1"},
+		{443, "[::1]:80", "https://[::1]/api/v1?x=1"},
+		{8443, "[::1]", "https://[::1]:8443/api/v1?x=1"},
+	}
+
+	for _, v := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1?x=1", nil)
+		request.Host = v.host
+		recorder := httptest.NewRecorder()
+		NewRedirect(v.port).ServeHTTP(recorder, request)
+
+		debug.AssertEqual(t, recorder.Code, http.StatusMovedPermanently)
+		debug.AssertEqual(t, recorder.Header().Get("Location"), v.expect)
+	}
+}
+
+func leafName(t *testing.T, loader *CertificateLoader) string {
+	certificate, err := loader.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func writeCertificate(t *testing.T, certFile, keyFile, name string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
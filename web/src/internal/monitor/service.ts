@@ -56,8 +56,9 @@ class MonitorService extends Service {
     }
 
     async getMeasurement(token: string, id: number, after?: DetachedState) {
-        let address = `/monitor/${id}/measurements`
-        if (after) address += `?after=${after.toAfterDate()}`
+        // The table shows individual measurements, so rollups are never requested.
+        let address = `/monitor/${id}/measurements?resolution=raw`
+        if (after) address += `&after=${after.toAfterDate()}`
         const request = new AuthenticatedRequest(token, address).method(GET_API)
         return await this.extract(request);
     }